@@ -0,0 +1,33 @@
+package paynow_sdk
+
+import "context"
+
+// Names of the endpoint methods, used as span names and in per-endpoint configuration.
+const (
+	OperationCreatePayment         = "CreatePayment"
+	OperationGetPaymentStatus      = "GetPaymentStatus"
+	OperationGetPaymentMethods     = "GetPaymentMethods"
+	OperationGetGDPRClauses        = "GetGDPRClauses"
+	OperationCreateRefund          = "CreateRefund"
+	OperationGetRefundStatus       = "GetRefundStatus"
+	OperationCancelRefund          = "CancelRefund"
+	OperationPatchShopURLs         = "PatchShopURLs"
+	OperationRemoveSavedInstrument = "RemoveSavedInstrument"
+	OperationSendRequest           = "SendRequest" // Requests sent directly with SendGetRequest, SendPostRequest and the like
+)
+
+// PayNowApi lists the Paynow endpoint methods implemented by PayNowApiClient.
+// Depend on it instead of the concrete client to substitute fakes, e.g. paynowtest.MockClient, in tests.
+type PayNowApi interface {
+	CreatePayment(ctx context.Context, body *CreatePaymentRequest, idempotencyKey string) (*CreatePaymentResponse, error)
+	GetPaymentStatus(ctx context.Context, paymentId string) (*GetPaymentStatusResponse, error)
+	GetPaymentMethods(ctx context.Context, queryParameters *GetPaymentMethodsQuery) (*[]GetPaymentMethodsResponse, error)
+	GetGDPRClauses(ctx context.Context, locale string) (*[]GetGDPRClausesResponseItem, error)
+	CreateRefund(ctx context.Context, paymentId string, body *CreateRefundRequest, idempotencyKey string) (*CreateRefundResponse, error)
+	GetRefundStatus(ctx context.Context, refundId string) (*GetRefundStatusResponse, error)
+	CancelRefund(ctx context.Context, refundId string, idempotencyKey string) (*GetRefundStatusResponse, error)
+	PatchShopURLs(ctx context.Context, bodyObj *PatchShopURLsRequest, idempotencyKey string) error
+	RemoveSavedInstrument(ctx context.Context, externalBuyerId, token string, idempotencyKey string) error
+}
+
+var _ PayNowApi = (*PayNowApiClient)(nil)
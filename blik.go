@@ -0,0 +1,45 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPaymentNotConfirmed is returned by CreateBlikPayment when the payment ends in a status other than CONFIRMED.
+var ErrPaymentNotConfirmed = errors.New("paynow: payment not confirmed")
+
+// blikConfirmationTimeout bounds CreateBlikPayment when WaitOptions.Timeout is not set. A BLIK code is valid
+// for 2 minutes and the buyer has to confirm the payment in the banking app within that time.
+const blikConfirmationTimeout = 2 * time.Minute
+
+// CreateBlikPayment creates a white-label BLIK payment, where the buyer types the 6-digit code on the merchant's page,
+// and waits until the buyer confirms or rejects it in the banking app. body must have PaymentMethodId set to a BLIK
+// method with AuthorizationTypeCode and AuthorizationCode set to the code. opts control polling, the wait is limited
+// to 2 minutes when opts.Timeout is 0. Polls failing with a transient error are repeated, see WaitForPaymentStatus.
+// It returns the last payment status. When the payment ends in a status other than CONFIRMED the status is returned
+// together with an error wrapping ErrPaymentNotConfirmed.
+func (c *PayNowApiClient) CreateBlikPayment(ctx context.Context, body *CreatePaymentRequest, idempotencyKey string, opts *WaitOptions) (*GetPaymentStatusResponse, error) {
+	if body == nil || body.AuthorizationCode == "" {
+		var errs ValidationErrors
+		errs.required("authorizationCode", "")
+		return nil, fmt.Errorf("failed to create BLIK payment: %w: %w", ErrInvalidRequest, errs)
+	}
+	payment, err := c.CreatePayment(ctx, body, idempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BLIK payment: %w", err)
+	}
+	options := opts.withDefaults()
+	if options.Timeout == 0 {
+		options.Timeout = blikConfirmationTimeout
+	}
+	status, _, err := c.WaitForPaymentStatus(ctx, payment.PaymentId, &options)
+	if err != nil {
+		return status, fmt.Errorf("failed to confirm BLIK payment: %w", err)
+	}
+	if status.Status != PaymentStatusConfirmed {
+		return status, fmt.Errorf("%w: BLIK payment %s ended with status %s", ErrPaymentNotConfirmed, payment.PaymentId, status.Status)
+	}
+	return status, nil
+}
@@ -0,0 +1,75 @@
+package paynow_sdk_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Hkozacz/paynow-gosdk"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func blikPaymentRequest() *paynow_sdk.CreatePaymentRequest {
+	return &paynow_sdk.CreatePaymentRequest{
+		Amount:            1000,
+		ExternalId:        "order-1",
+		Description:       "Order 1",
+		Buyer:             &paynow_sdk.BuyerInfo{Email: "jan.kowalski@example.com"},
+		PaymentMethodId:   2007,
+		AuthorizationCode: "123456",
+	}
+}
+
+func TestCreateBlikPayment(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int // Status of the request failing after the first poll
+		final      paynow_sdk.PaymentStatus
+		wantErr    error
+	}{
+		{name: "confirmed after 503", statusCode: http.StatusServiceUnavailable, final: paynow_sdk.PaymentStatusConfirmed},
+		{name: "confirmed after 429", statusCode: http.StatusTooManyRequests, final: paynow_sdk.PaymentStatusConfirmed},
+		{name: "rejected after 503", statusCode: http.StatusServiceUnavailable, final: paynow_sdk.PaymentStatusRejected, wantErr: paynow_sdk.ErrPaymentNotConfirmed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newWaitTestServer(t)
+			middleware, polls := settleAfterFailedPoll(t, server, tc.statusCode, tc.final)
+			client := server.Client(paynow_sdk.WithMiddleware(middleware))
+			defer client.Close()
+
+			status, err := client.CreateBlikPayment(context.Background(), blikPaymentRequest(), "order-1", &paynow_sdk.WaitOptions{
+				Interval: time.Millisecond,
+				Timeout:  5 * time.Second,
+			})
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Fatalf("CreateBlikPayment() error = %v, want %v", err, tc.wantErr)
+			}
+			if status == nil || status.Status != tc.final {
+				t.Fatalf("CreateBlikPayment() status = %+v, want %s", status, tc.final)
+			}
+			if got := polls.Load(); got != 3 {
+				t.Errorf("polled %d times, want 3", got)
+			}
+		})
+	}
+}
+
+func TestCreateBlikPaymentStopsOnClientErrors(t *testing.T) {
+	server := newWaitTestServer(t)
+	middleware, polls := settleAfterFailedPoll(t, server, http.StatusForbidden, paynow_sdk.PaymentStatusConfirmed)
+	client := server.Client(paynow_sdk.WithMiddleware(middleware))
+	defer client.Close()
+
+	_, err := client.CreateBlikPayment(context.Background(), blikPaymentRequest(), "order-1", &paynow_sdk.WaitOptions{
+		Interval: time.Millisecond,
+		Timeout:  5 * time.Second,
+	})
+	var apiErr *paynow_sdk.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("CreateBlikPayment() error = %v, want the 403 API error", err)
+	}
+	if got := polls.Load(); got != 2 {
+		t.Errorf("polled %d times, want 2", got)
+	}
+}
@@ -0,0 +1,219 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting Paynow while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("paynow: circuit breaker is open")
+
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests are sent and their results counted
+	CircuitOpen                         // Requests fail fast with ErrCircuitOpen
+	CircuitHalfOpen                     // A limited number of probe requests is let through
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerPolicy configures a CircuitBreaker. Network errors and 5xx responses count as failures,
+// other responses, including 4xx, count as successes. Requests cancelled by the caller are not counted.
+type CircuitBreakerPolicy struct {
+	FailureRatio   float64                     // Ratio of failed requests opening the circuit, 0.5 when not in (0, 1]
+	MinRequests    int                         // Requests needed in a window before the ratio is checked, 10 when below 1
+	Window         time.Duration               // Length of the window in which requests are counted, 1 minute when 0
+	OpenTimeout    time.Duration               // How long the circuit stays open before probing, 30 seconds when 0
+	HalfOpenProbes int                         // Probes let through when half-open, all must succeed to close the circuit, 1 when below 1
+	OnStateChange  func(from, to CircuitState) // Optional hook called after every state change
+}
+
+const (
+	defaultCircuitFailureRatio = 0.5
+	defaultCircuitMinRequests  = 10
+	defaultCircuitWindow       = time.Minute
+	defaultCircuitOpenTimeout  = 30 * time.Second
+)
+
+// CircuitBreaker stops sending requests to Paynow after too many of them failed. Keep a reference to it
+// to report State in health checks; one breaker can be shared by several clients.
+type CircuitBreaker struct {
+	policy CircuitBreakerPolicy
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  int // Incremented on every state change, results of requests from older generations are ignored
+	requests    int
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+func NewCircuitBreaker(policy CircuitBreakerPolicy) *CircuitBreaker {
+	if policy.FailureRatio <= 0 || policy.FailureRatio > 1 {
+		policy.FailureRatio = defaultCircuitFailureRatio
+	}
+	if policy.MinRequests < 1 {
+		policy.MinRequests = defaultCircuitMinRequests
+	}
+	if policy.Window <= 0 {
+		policy.Window = defaultCircuitWindow
+	}
+	if policy.OpenTimeout <= 0 {
+		policy.OpenTimeout = defaultCircuitOpenTimeout
+	}
+	if policy.HalfOpenProbes < 1 {
+		policy.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{policy: policy, windowStart: time.Now()}
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen while breaker is open.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(o *clientOptions) {
+		o.circuitBreaker = breaker
+	}
+}
+
+// State returns the current state. An open circuit whose OpenTimeout has passed is reported as half-open.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.policy.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent and returns the generation its result belongs to.
+func (b *CircuitBreaker) allow() (int, error) {
+	b.mu.Lock()
+	now := time.Now()
+	var changed bool
+	var from CircuitState
+	switch b.state {
+	case CircuitClosed:
+		if now.Sub(b.windowStart) >= b.policy.Window {
+			b.requests, b.failures, b.windowStart = 0, 0, now
+		}
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < b.policy.OpenTimeout {
+			b.mu.Unlock()
+			return 0, ErrCircuitOpen
+		}
+		from, changed = b.setState(CircuitHalfOpen, now)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.policy.HalfOpenProbes {
+			b.mu.Unlock()
+			return 0, ErrCircuitOpen
+		}
+		b.probes++
+	}
+	generation := b.generation
+	b.mu.Unlock()
+	if changed {
+		b.notify(from, CircuitHalfOpen)
+	}
+	return generation, nil
+}
+
+func (b *CircuitBreaker) record(generation int, failed bool) {
+	b.mu.Lock()
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	var changed bool
+	var from CircuitState
+	switch b.state {
+	case CircuitClosed:
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= b.policy.MinRequests && float64(b.failures)/float64(b.requests) >= b.policy.FailureRatio {
+			from, changed = b.setState(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			from, changed = b.setState(CircuitOpen, now)
+			break
+		}
+		b.successes++
+		if b.successes >= b.policy.HalfOpenProbes {
+			from, changed = b.setState(CircuitClosed, now)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	if changed {
+		b.notify(from, to)
+	}
+}
+
+// setState must be called with b.mu held. It returns the previous state and whether it changed.
+func (b *CircuitBreaker) setState(state CircuitState, now time.Time) (CircuitState, bool) {
+	from := b.state
+	if from == state {
+		return from, false
+	}
+	b.state = state
+	b.generation++
+	b.requests, b.failures, b.probes, b.successes = 0, 0, 0, 0
+	b.windowStart = now
+	if state == CircuitOpen {
+		b.openedAt = now
+	}
+	return from, true
+}
+
+func (b *CircuitBreaker) notify(from, to CircuitState) {
+	if b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(from, to)
+	}
+}
+
+func (b *CircuitBreaker) middleware(next Handler) Handler {
+	return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+		generation, err := b.allow()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next(ctx, req)
+		if err != nil && (ctx.Err() != nil || errors.Is(err, errRateLimitWait)) {
+			// Cancelled by the caller or never sent, says nothing about Paynow. Free the probe slot if half-open.
+			b.release(generation)
+			return resp, err
+		}
+		b.record(generation, err != nil || resp.StatusCode >= http.StatusInternalServerError)
+		return resp, err
+	}
+}
+
+// release gives back a half-open probe slot taken by a request whose result is not counted.
+func (b *CircuitBreaker) release(generation int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation == b.generation && b.state == CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
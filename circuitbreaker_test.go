@@ -0,0 +1,222 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// circuitTest drives a CircuitBreaker through its middleware and records its state changes.
+type circuitTest struct {
+	t       *testing.T
+	breaker *CircuitBreaker
+
+	mu      sync.Mutex
+	changes []string
+}
+
+func newCircuitTest(t *testing.T, policy CircuitBreakerPolicy) *circuitTest {
+	ct := &circuitTest{t: t}
+	policy.OnStateChange = func(from, to CircuitState) {
+		ct.mu.Lock()
+		defer ct.mu.Unlock()
+		ct.changes = append(ct.changes, from.String()+"->"+to.String())
+	}
+	ct.breaker = NewCircuitBreaker(policy)
+	return ct
+}
+
+// send makes a request answered with status, or failing with a transport error when status is 0.
+func (ct *circuitTest) send(ctx context.Context, status int) error {
+	handler := ct.breaker.middleware(func(ctx context.Context, _ *APIRequest) (*APIResponse, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if status == 0 {
+			return nil, errors.New("connection reset")
+		}
+		return &APIResponse{StatusCode: status}, nil
+	})
+	_, err := handler(ctx, &APIRequest{})
+	return err
+}
+
+func (ct *circuitTest) sendAll(statuses ...int) {
+	ct.t.Helper()
+	for _, status := range statuses {
+		if err := ct.send(context.Background(), status); errors.Is(err, ErrCircuitOpen) {
+			ct.t.Fatalf("request answered with %d got ErrCircuitOpen", status)
+		}
+	}
+}
+
+func (ct *circuitTest) wantState(want CircuitState) {
+	ct.t.Helper()
+	if got := ct.breaker.State(); got != want {
+		ct.t.Fatalf("State() = %s, want %s", got, want)
+	}
+}
+
+// expireOpenTimeout moves the opening of the circuit back by OpenTimeout.
+func (ct *circuitTest) expireOpenTimeout() {
+	ct.breaker.mu.Lock()
+	defer ct.breaker.mu.Unlock()
+	ct.breaker.openedAt = ct.breaker.openedAt.Add(-ct.breaker.policy.OpenTimeout)
+}
+
+func (ct *circuitTest) wantChanges(want ...string) {
+	ct.t.Helper()
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if !slices.Equal(ct.changes, want) {
+		ct.t.Errorf("OnStateChange calls = %q, want %q", ct.changes, want)
+	}
+}
+
+func TestCircuitBreakerOpensAtFailureRatio(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []int
+		want     CircuitState
+	}{
+		{name: "below MinRequests", statuses: []int{0, 0, 0}, want: CircuitClosed},
+		{name: "below FailureRatio", statuses: []int{200, 0, 200, 200}, want: CircuitClosed},
+		{name: "at FailureRatio", statuses: []int{200, 0, 200, 503}, want: CircuitOpen},
+		{name: "4xx counted as success", statuses: []int{400, 404, 429, 0}, want: CircuitClosed},
+		{name: "transport errors and 5xx", statuses: []int{0, 500, 0, 502}, want: CircuitOpen},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ct := newCircuitTest(t, CircuitBreakerPolicy{FailureRatio: 0.5, MinRequests: 4, OpenTimeout: time.Hour})
+			ct.sendAll(tc.statuses...)
+			ct.wantState(tc.want)
+		})
+	}
+}
+
+func TestCircuitBreakerWindowResetsCounts(t *testing.T) {
+	ct := newCircuitTest(t, CircuitBreakerPolicy{FailureRatio: 0.5, MinRequests: 4, OpenTimeout: time.Hour})
+	ct.sendAll(0, 0, 0)
+	ct.breaker.mu.Lock()
+	ct.breaker.windowStart = ct.breaker.windowStart.Add(-ct.breaker.policy.Window)
+	ct.breaker.mu.Unlock()
+	ct.sendAll(0)
+	ct.wantState(CircuitClosed)
+}
+
+func TestCircuitBreakerFailsFastWhileOpen(t *testing.T) {
+	ct := newCircuitTest(t, CircuitBreakerPolicy{MinRequests: 1, OpenTimeout: time.Hour})
+	ct.sendAll(503)
+	ct.wantState(CircuitOpen)
+
+	called := false
+	handler := ct.breaker.middleware(func(context.Context, *APIRequest) (*APIResponse, error) {
+		called = true
+		return &APIResponse{StatusCode: http.StatusOK}, nil
+	})
+	if _, err := handler(context.Background(), &APIRequest{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error = %v, want ErrCircuitOpen", err)
+	}
+	if called {
+		t.Error("request sent while the circuit is open")
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbes(t *testing.T) {
+	ct := newCircuitTest(t, CircuitBreakerPolicy{MinRequests: 1, OpenTimeout: time.Hour, HalfOpenProbes: 2})
+	ct.sendAll(503)
+	ct.expireOpenTimeout()
+	ct.wantState(CircuitHalfOpen)
+
+	// Two probes in flight take both slots, a third request fails fast.
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	probe := ct.breaker.middleware(func(context.Context, *APIRequest) (*APIResponse, error) {
+		started <- struct{}{}
+		<-release
+		return &APIResponse{StatusCode: http.StatusOK}, nil
+	})
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := probe(context.Background(), &APIRequest{}); err != nil {
+				t.Errorf("probe error = %v", err)
+			}
+		}()
+	}
+	<-started
+	<-started
+	if err := ct.send(context.Background(), http.StatusOK); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("third request error = %v, want ErrCircuitOpen", err)
+	}
+	close(release)
+	wg.Wait()
+	ct.wantState(CircuitClosed)
+	ct.wantChanges("closed->open", "open->half-open", "half-open->closed")
+}
+
+func TestCircuitBreakerReopensOnFailedProbe(t *testing.T) {
+	for _, status := range []int{0, http.StatusServiceUnavailable} {
+		t.Run(fmt.Sprint(status), func(t *testing.T) {
+			ct := newCircuitTest(t, CircuitBreakerPolicy{MinRequests: 1, OpenTimeout: time.Hour, HalfOpenProbes: 2})
+			ct.sendAll(503)
+			ct.expireOpenTimeout()
+			ct.sendAll(200, status)
+			ct.wantState(CircuitOpen)
+			if err := ct.send(context.Background(), http.StatusOK); !errors.Is(err, ErrCircuitOpen) {
+				t.Errorf("error = %v, want ErrCircuitOpen after the failed probe", err)
+			}
+			ct.wantChanges("closed->open", "open->half-open", "half-open->open")
+		})
+	}
+}
+
+func TestCircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("closed", func(t *testing.T) {
+		ct := newCircuitTest(t, CircuitBreakerPolicy{MinRequests: 1, OpenTimeout: time.Hour})
+		for range 3 {
+			if err := ct.send(cancelled, 0); !errors.Is(err, context.Canceled) {
+				t.Fatalf("error = %v, want context.Canceled", err)
+			}
+		}
+		ct.wantState(CircuitClosed)
+		ct.wantChanges()
+	})
+
+	t.Run("half-open releases the probe slot", func(t *testing.T) {
+		ct := newCircuitTest(t, CircuitBreakerPolicy{MinRequests: 1, OpenTimeout: time.Hour, HalfOpenProbes: 1})
+		ct.sendAll(503)
+		ct.expireOpenTimeout()
+		if err := ct.send(cancelled, 0); !errors.Is(err, context.Canceled) {
+			t.Fatalf("error = %v, want context.Canceled", err)
+		}
+		ct.wantState(CircuitHalfOpen)
+		// The slot of the cancelled probe is free again.
+		if err := ct.send(context.Background(), http.StatusOK); err != nil {
+			t.Fatalf("probe error = %v", err)
+		}
+		ct.wantState(CircuitClosed)
+	})
+}
+
+func TestCircuitBreakerStateChangeOrder(t *testing.T) {
+	ct := newCircuitTest(t, CircuitBreakerPolicy{MinRequests: 2, FailureRatio: 1, OpenTimeout: time.Hour})
+	ct.sendAll(0, 0)
+	ct.expireOpenTimeout()
+	ct.sendAll(503)
+	ct.expireOpenTimeout()
+	ct.sendAll(200)
+	ct.sendAll(200, 500)
+	ct.wantState(CircuitClosed)
+	ct.wantChanges("closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed")
+}
@@ -1,227 +1,367 @@
 package paynow_sdk
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"reflect"
 	"resty.dev/v3"
+	"strconv"
+	"sync"
+	"time"
 )
 
 type PayNowApiClient struct {
-	apiKey  string
-	secret  string
-	baseUrl string
+	apiKey      string
+	secret      string
+	baseUrl     string
+	httpClient  *resty.Client
+	retryPolicy *RetryPolicy
+	rateLimiter *rateLimiter // Also used by retryPolicy, so retries take tokens; nil without WithRateLimit
+	validate    bool
+	logger      *slog.Logger
+	tracer      trace.Tracer
+	metrics     MetricsHook
+	handler     Handler // Middleware chain ending with the circuit breaker, the rate limiter and execute
+	closeOnce   sync.Once
 }
 
-func NewPayNowApiClient(apiKey, secret, baseUrl string) *PayNowApiClient {
-	return &PayNowApiClient{
-		apiKey:  apiKey,
-		secret:  secret,
-		baseUrl: baseUrl,
+// NewPayNowApiClient creates a client holding a single long-lived HTTP transport
+// that is shared by every endpoint method. Call Close when the client is no longer needed.
+// baseUrl is usually Production or Sandbox; a missing trailing slash is added, malformed URLs return ErrInvalidBaseURL.
+func NewPayNowApiClient(apiKey, secret, baseUrl string, opts ...Option) (*PayNowApiClient, error) {
+	baseUrl, err := normalizeBaseURL(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	options := defaultClientOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	var httpClient *resty.Client
+	if options.httpClient != nil {
+		// A copy, so the transport, proxy and timeout set below do not change the caller's client.
+		clone := *options.httpClient
+		httpClient = resty.NewWithClient(&clone)
+	} else {
+		httpClient = resty.New()
+	}
+	if options.transport != nil {
+		httpClient.SetTransport(options.transport)
+	}
+	if options.proxyURL != "" {
+		transport, err := proxyTransport(httpClient.Transport(), options.proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.SetTransport(transport)
+	}
+	httpClient.SetTimeout(options.timeout)
+	httpClient.SetHeader("User-Agent", options.userAgent)
+	if options.retryPolicy != nil {
+		options.retryPolicy.apply(httpClient)
+	}
+	client := &PayNowApiClient{
+		apiKey:      apiKey,
+		secret:      secret,
+		baseUrl:     baseUrl,
+		httpClient:  httpClient,
+		retryPolicy: options.retryPolicy,
+		validate:    options.validate,
+		logger:      options.logger,
+		tracer:      options.tracer,
+		metrics:     options.metrics,
+	}
+	handler := Handler(client.execute)
+	if options.rateLimit != nil {
+		client.rateLimiter = newRateLimiter(*options.rateLimit)
+		handler = client.rateLimiter.middleware(handler)
 	}
+	if options.circuitBreaker != nil {
+		handler = options.circuitBreaker.middleware(handler)
+	}
+	client.handler = chain(handler, options.middlewares)
+	return client, nil
+}
+
+// Close releases resources held by the underlying HTTP transport. Calling it more than once is safe.
+func (c *PayNowApiClient) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.httpClient.Close()
+	})
+	return err
+}
+
+// validateRequest runs request.Validate unless validation was disabled with WithValidation(false).
+func (c *PayNowApiClient) validateRequest(request RequestType) error {
+	if !c.validate || request == nil {
+		return nil
+	}
+	if value := reflect.ValueOf(request); value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil
+	}
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return nil
 }
 
-func (c *PayNowApiClient) SendPostRequest(endpoint, idempotencyKey string, bodyObj RequestType, responseObj, responseErrorObj interface{}) error {
+// validateId rejects an empty identifier placed in the endpoint path, which would address another endpoint.
+// It runs even when WithValidation is disabled.
+func validateId(field, value string) error {
+	var errs ValidationErrors
+	errs.required(field, value)
+	if err := errs.errOrNil(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+func (c *PayNowApiClient) SendPostRequest(ctx context.Context, endpoint, idempotencyKey string, bodyObj RequestType, responseObj interface{}) error {
+	if err := c.validateRequest(bodyObj); err != nil {
+		return err
+	}
 	body, err := json.Marshal(bodyObj)
-	parsedBody := string(body)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
-	signature, err := GenerateV3(c.apiKey, c.secret, idempotencyKey, parsedBody, nil)
-	if err != nil {
-		return fmt.Errorf("failed to generate signature: %w", err)
+	return c.send(ctx, http.MethodPost, endpoint, idempotencyKey, bodyObj, string(body), nil, responseObj)
+}
+
+func (c *PayNowApiClient) SendGetRequest(ctx context.Context, endpoint, idempotencyKey string, queryParams RequestType, responseObj interface{}) error {
+	return c.sendWithQuery(ctx, http.MethodGet, endpoint, idempotencyKey, queryParams, responseObj)
+}
+
+func (c *PayNowApiClient) SendDeleteRequest(ctx context.Context, endpoint, idempotencyKey string, queryParams RequestType, responseObj interface{}) error {
+	return c.sendWithQuery(ctx, http.MethodDelete, endpoint, idempotencyKey, queryParams, responseObj)
+}
+
+func (c *PayNowApiClient) sendWithQuery(ctx context.Context, method, endpoint, idempotencyKey string, queryParams RequestType, responseObj interface{}) error {
+	if err := c.validateRequest(queryParams); err != nil {
+		return err
 	}
-	client := resty.New()
-	defer client.Close()
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Api-Key", c.apiKey).
-		SetHeader("Idempotency-Key", idempotencyKey).
-		SetHeader("Signature", signature).
-		SetBody(parsedBody).
-		SetResult(responseObj).
-		SetError(responseErrorObj).
-		Post(c.baseUrl + endpoint)
+	queryParamsMap, err := queryParameters(queryParams)
 	if err != nil {
-		return fmt.Errorf("failed to send POST request: %w", err)
-	}
-	if resp.IsError() {
-		return fmt.Errorf("error response from server: %s Status: %s", resp.String(), resp.Status())
+		return err
 	}
-	return nil
+	return c.send(ctx, method, endpoint, idempotencyKey, queryParams, "", queryParamsMap, responseObj)
 }
 
-func (c *PayNowApiClient) SendGetRequest(endpoint, idempotencyKey string, queryParams RequestType, responseObj, responseErrorObj interface{}) error {
+// queryParameters flattens a query struct into the parameters sent in the URL and covered by the signature.
+// Numbers keep their exact JSON representation and null values are skipped.
+func queryParameters(queryParams RequestType) (map[string]string, error) {
 	queryParamsMap := make(map[string]string)
-	if queryParams != nil {
-		queryParamsBytes, err := json.Marshal(queryParams)
-		if err != nil {
-			return fmt.Errorf("failed to marshal query parameters: %w", err)
-		}
-		if err := json.Unmarshal(queryParamsBytes, &queryParamsMap); err != nil {
-			return fmt.Errorf("failed to unmarshal query parameters: %w", err)
+	if queryParams == nil {
+		return queryParamsMap, nil
+	}
+	queryParamsBytes, err := json.Marshal(queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query parameters: %w", err)
+	}
+	var values map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(queryParamsBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal query parameters: %w", err)
+	}
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			queryParamsMap[key] = v
+		case json.Number:
+			queryParamsMap[key] = v.String()
+		case bool:
+			queryParamsMap[key] = strconv.FormatBool(v)
+		case nil:
+		default:
+			return nil, fmt.Errorf("failed to convert query parameter %s: unsupported type %T", key, value)
 		}
 	}
-	signature, err := GenerateV3(c.apiKey, c.secret, idempotencyKey, "", queryParamsMap)
+	return queryParamsMap, nil
+}
+
+// send signs a request and passes it through the middleware chain, every endpoint method goes through it.
+func (c *PayNowApiClient) send(ctx context.Context, method, endpoint, idempotencyKey string, requestObj RequestType, body string, queryParams map[string]string, responseObj interface{}) error {
+	signature, err := GenerateV3(c.apiKey, c.secret, idempotencyKey, body, queryParams)
 	if err != nil {
 		return fmt.Errorf("failed to generate signature: %w", err)
 	}
-	client := resty.New()
-	defer client.Close()
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Api-Key", c.apiKey).
-		SetHeader("Idempotency-Key", idempotencyKey).
-		SetHeader("Signature", signature).
-		SetQueryParams(queryParamsMap).
-		SetResult(responseObj).
-		SetError(responseErrorObj).
-		Get(c.baseUrl + endpoint)
+	request := &APIRequest{
+		Operation: operationFromContext(ctx, OperationSendRequest),
+		Method:    method,
+		Endpoint:  endpoint,
+		URL:       joinURL(c.baseUrl, endpoint),
+		Header:    http.Header{},
+		Query:     url.Values{},
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Api-Key", c.apiKey)
+	request.Header.Set("Idempotency-Key", idempotencyKey)
+	request.Header.Set("Signature", signature)
+	for key, value := range queryParams {
+		request.Query.Set(key, value)
+	}
+	if method != http.MethodGet {
+		request.Body = []byte(body)
+	}
+	c.logRequest(ctx, request, requestObj)
+	start := time.Now()
+	resp, err := c.handler(ctx, request)
 	if err != nil {
-		return fmt.Errorf("failed to send GET request: %w", err)
+		err = fmt.Errorf("failed to send %s request: %w", method, err)
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		err = newAPIError(request.Operation, resp)
+	} else if responseObj != nil && len(bytes.TrimSpace(resp.Body)) != 0 {
+		if err = json.Unmarshal(resp.Body, responseObj); err != nil {
+			err = fmt.Errorf("failed to decode response body: %w", err)
+		}
 	}
-	if resp.IsError() {
-		return fmt.Errorf("error response from server: %s Status: %s", resp.String(), resp.Status())
+	duration := time.Since(start)
+	c.logResponse(ctx, request, resp, duration, err)
+	metrics := RequestMetrics{
+		Operation: request.Operation,
+		Method:    method,
+		Err:       err,
+		Duration:  duration,
 	}
-	return nil
+	if resp != nil {
+		metrics.StatusCode = resp.StatusCode
+	}
+	if span := clientSpanFromContext(ctx); span != nil {
+		span.SetAttributes(AttributeEndpoint.String(endpoint), AttributeHTTPMethod.String(method))
+		if resp != nil {
+			span.SetAttributes(AttributeHTTPStatusCode.Int(resp.StatusCode))
+		}
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		metrics.ErrorTypes = apiErr.ErrorTypes()
+	}
+	c.metrics.ObserveRequest(metrics)
+	return err
 }
 
-func (c *PayNowApiClient) CreatePayment(body *CreatePaymentRequest, idempotencyKey string) (*CreatePaymentResponse, error) {
+func (c *PayNowApiClient) CreatePayment(ctx context.Context, body *CreatePaymentRequest, idempotencyKey string) (*CreatePaymentResponse, error) {
+	ctx, span := c.startSpan(ctx, OperationCreatePayment)
+	defer span.End()
 	responseObj := &CreatePaymentResponse{}
-	responseErrorObj := &ErrorResponse{}
-	err := c.SendPostRequest("payments", idempotencyKey, body, responseObj, responseErrorObj)
-	if err != nil {
-		if responseErrorObj != nil && responseErrorObj.StatusCode != 0 {
-			parsedErrorResponse, parsingErr := json.Marshal(responseErrorObj)
-			if parsingErr == nil {
-				return nil, fmt.Errorf("error creating payment: %s", string(parsedErrorResponse))
-			}
-		}
-		return nil, fmt.Errorf("failed to create payment: %w", err)
+	if err := c.SendPostRequest(ctx, "payments", idempotencyKey, body, responseObj); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to create payment: %w", err))
 	}
+	span.SetAttributes(AttributePaymentId.String(responseObj.PaymentId), AttributeStatus.String(string(responseObj.Status)))
 	return responseObj, nil
 }
 
-func (c *PayNowApiClient) GetPaymentStatus(paymentId string) (*GetPaymentStatusResponse, error) {
+func (c *PayNowApiClient) GetPaymentStatus(ctx context.Context, paymentId string) (*GetPaymentStatusResponse, error) {
+	ctx, span := c.startSpan(ctx, OperationGetPaymentStatus, AttributePaymentId.String(paymentId))
+	defer span.End()
+	if err := validateId("paymentId", paymentId); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to get payment status: %w", err))
+	}
 	responseObj := &GetPaymentStatusResponse{}
-	responseErrorObj := &ErrorResponse{}
-	err := c.SendGetRequest("payments/"+paymentId+"/status", uuid.New().String(), nil, responseObj, responseErrorObj)
-	if err != nil {
-		if responseErrorObj != nil && responseErrorObj.StatusCode != 0 {
-			parsedErrorResponse, parsingErr := json.Marshal(responseErrorObj)
-			if parsingErr == nil {
-				return nil, fmt.Errorf("error getting payment status: %s", string(parsedErrorResponse))
-			}
-		}
-		return nil, fmt.Errorf("failed to get payment status: %w", err)
+	if err := c.SendGetRequest(ctx, endpointPath("payments", paymentId, "status"), uuid.New().String(), nil, responseObj); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to get payment status: %w", err))
 	}
+	span.SetAttributes(AttributeStatus.String(string(responseObj.Status)))
 	return responseObj, nil
 }
 
-func (c *PayNowApiClient) GetPaymentMethods(queryParameters *GetPaymentMethodsQuery) (*[]GetPaymentMethodsResponse, error) {
+func (c *PayNowApiClient) GetPaymentMethods(ctx context.Context, queryParameters *GetPaymentMethodsQuery) (*[]GetPaymentMethodsResponse, error) {
+	ctx, span := c.startSpan(ctx, OperationGetPaymentMethods)
+	defer span.End()
 	responseObj := &[]GetPaymentMethodsResponse{}
-	responseErrorObj := &ErrorResponse{}
-	err := c.SendGetRequest("payments/paymentmethods", uuid.New().String(), queryParameters, responseObj, responseErrorObj)
-	if err != nil {
-		if responseErrorObj != nil && responseErrorObj.StatusCode != 0 {
-			parsedErrorResponse, parsingErr := json.Marshal(responseErrorObj)
-			if parsingErr == nil {
-				return nil, fmt.Errorf("error getting payment methods: %s", string(parsedErrorResponse))
-			}
-		}
-		return nil, fmt.Errorf("failed to get payment methods: %w", err)
+	if err := c.SendGetRequest(ctx, "payments/paymentmethods", uuid.New().String(), queryParameters, responseObj); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to get payment methods: %w", err))
 	}
 	return responseObj, nil
 }
 
-func (c *PayNowApiClient) GetGDPRClauses() (*[]GetGDPRClausesResponseItem, error) {
+// GetGDPRClauses returns the GDPR notices to show to the buyer in the given language, e.g. "pl-PL".
+// An empty locale leaves the choice to Paynow. See GDPRNoticeCache for caching and locale fallback.
+func (c *PayNowApiClient) GetGDPRClauses(ctx context.Context, locale string) (*[]GetGDPRClausesResponseItem, error) {
+	ctx, span := c.startSpan(ctx, OperationGetGDPRClauses)
+	defer span.End()
 	responseObj := &[]GetGDPRClausesResponseItem{}
-	responseErrorObj := &ErrorResponse{}
-	err := c.SendGetRequest("payments/dataprocessing/notices", uuid.New().String(), nil, responseObj, responseErrorObj)
-	if err != nil {
-		if responseErrorObj != nil && responseErrorObj.StatusCode != 0 {
-			parsedErrorResponse, parsingErr := json.Marshal(responseErrorObj)
-			if parsingErr == nil {
-				return nil, fmt.Errorf("error getting GDPR clauses: %s", string(parsedErrorResponse))
-			}
-		}
-		return nil, fmt.Errorf("failed to get GDPR clauses: %w", err)
+	query := &GetGDPRClausesQuery{Locale: locale}
+	if err := c.SendGetRequest(ctx, "payments/dataprocessing/notices", uuid.New().String(), query, responseObj); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to get GDPR clauses: %w", err))
 	}
 	return responseObj, nil
 }
 
-func (c *PayNowApiClient) CreateRefund(paymentId string, body *CreateRefundRequest, idempotencyKey string) (*CreateRefundResponse, error) {
+func (c *PayNowApiClient) CreateRefund(ctx context.Context, paymentId string, body *CreateRefundRequest, idempotencyKey string) (*CreateRefundResponse, error) {
+	ctx, span := c.startSpan(ctx, OperationCreateRefund, AttributePaymentId.String(paymentId))
+	defer span.End()
+	if err := validateId("paymentId", paymentId); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to create refund: %w", err))
+	}
 	responseObj := &CreateRefundResponse{}
-	responseErrorObj := &ErrorResponse{}
-	err := c.SendPostRequest("payments/"+paymentId+"/refunds", idempotencyKey, body, responseObj, responseErrorObj)
-	if err != nil {
-		if responseErrorObj != nil && responseErrorObj.StatusCode != 0 {
-			parsedErrorResponse, parsingErr := json.Marshal(responseErrorObj)
-			if parsingErr == nil {
-				return nil, fmt.Errorf("error creating refund: %s", string(parsedErrorResponse))
-			}
-		}
-		return nil, fmt.Errorf("failed to create refund: %w", err)
+	if err := c.SendPostRequest(ctx, endpointPath("payments", paymentId, "refunds"), idempotencyKey, body, responseObj); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to create refund: %w", err))
 	}
+	span.SetAttributes(AttributeRefundId.String(responseObj.RefundId), AttributeStatus.String(string(responseObj.Status)))
 	return responseObj, nil
 }
 
-func (c *PayNowApiClient) GetRefundStatus(refundId string) (*GetRefundStatusResponse, error) {
+func (c *PayNowApiClient) GetRefundStatus(ctx context.Context, refundId string) (*GetRefundStatusResponse, error) {
+	ctx, span := c.startSpan(ctx, OperationGetRefundStatus, AttributeRefundId.String(refundId))
+	defer span.End()
+	if err := validateId("refundId", refundId); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to get refund status: %w", err))
+	}
 	responseObj := &GetRefundStatusResponse{}
-	responseErrorObj := &ErrorResponse{}
-	err := c.SendGetRequest("refunds/"+refundId+"/status", uuid.New().String(), nil, responseObj, responseErrorObj)
-	if err != nil {
-		if responseErrorObj != nil && responseErrorObj.StatusCode != 0 {
-			parsedErrorResponse, parsingErr := json.Marshal(responseErrorObj)
-			if parsingErr == nil {
-				return nil, fmt.Errorf("error getting refund status: %s", string(parsedErrorResponse))
-			}
-		}
-		return nil, fmt.Errorf("failed to get refund status: %w", err)
+	if err := c.SendGetRequest(ctx, endpointPath("refunds", refundId, "status"), uuid.New().String(), nil, responseObj); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to get refund status: %w", err))
 	}
+	span.SetAttributes(AttributeStatus.String(string(responseObj.Status)))
 	return responseObj, nil
 }
 
-func (c *PayNowApiClient) CancelRefund(refundId string, idempotencyKey string) (*GetRefundStatusResponse, error) {
+func (c *PayNowApiClient) CancelRefund(ctx context.Context, refundId string, idempotencyKey string) (*GetRefundStatusResponse, error) {
+	ctx, span := c.startSpan(ctx, OperationCancelRefund, AttributeRefundId.String(refundId))
+	defer span.End()
+	if err := validateId("refundId", refundId); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to cancel refund: %w", err))
+	}
 	responseObj := &GetRefundStatusResponse{}
-	responseErrorObj := &ErrorResponse{}
-	err := c.SendPostRequest("refunds/"+refundId+"/cancel", idempotencyKey, nil, responseObj, responseErrorObj)
-	if err != nil {
-		if responseErrorObj != nil && responseErrorObj.StatusCode != 0 {
-			parsedErrorResponse, parsingErr := json.Marshal(responseErrorObj)
-			if parsingErr == nil {
-				return nil, fmt.Errorf("error canceling refund: %s", string(parsedErrorResponse))
-			}
-		}
-		return nil, fmt.Errorf("failed to cancel refund: %w", err)
+	if err := c.SendPostRequest(ctx, endpointPath("refunds", refundId, "cancel"), idempotencyKey, nil, responseObj); err != nil {
+		return nil, recordSpanError(span, fmt.Errorf("failed to cancel refund: %w", err))
 	}
+	span.SetAttributes(AttributeStatus.String(string(responseObj.Status)))
 	return responseObj, nil
 }
 
-func (c *PayNowApiClient) PatchShopURLs(bodyObj *PatchShopURLsRequest, idempotencyKey string) error {
+func (c *PayNowApiClient) PatchShopURLs(ctx context.Context, bodyObj *PatchShopURLsRequest, idempotencyKey string) error {
+	ctx, span := c.startSpan(ctx, OperationPatchShopURLs)
+	defer span.End()
+	if err := c.validateRequest(bodyObj); err != nil {
+		return recordSpanError(span, err)
+	}
 	body, err := json.Marshal(bodyObj)
-	parsedBody := string(body)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
+		return recordSpanError(span, fmt.Errorf("failed to marshal request body: %w", err))
 	}
-	signature, err := GenerateV3(c.apiKey, c.secret, idempotencyKey, parsedBody, nil)
-	if err != nil {
-		return fmt.Errorf("failed to generate signature: %w", err)
+	if err := c.send(ctx, http.MethodPatch, "configuration/shop/urls", idempotencyKey, bodyObj, string(body), nil, nil); err != nil {
+		return recordSpanError(span, err)
 	}
-	client := resty.New()
-	defer client.Close()
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Api-Key", c.apiKey).
-		SetHeader("Idempotency-Key", idempotencyKey).
-		SetHeader("Signature", signature).
-		SetBody(parsedBody).
-		Patch(c.baseUrl + "configuration/shop/urls")
-	if err != nil {
-		return fmt.Errorf("failed to send POST request: %w", err)
-	}
-	if resp.IsError() {
-		return fmt.Errorf("error response from server: %s Status: %s", resp.String(), resp.Status())
+	return nil
+}
+
+// RemoveSavedInstrument deletes a card saved by the buyer, identified by SavedInstrument.Token.
+func (c *PayNowApiClient) RemoveSavedInstrument(ctx context.Context, externalBuyerId, token string, idempotencyKey string) error {
+	ctx, span := c.startSpan(ctx, OperationRemoveSavedInstrument)
+	defer span.End()
+	query := &RemoveSavedInstrumentQuery{ExternalBuyerId: externalBuyerId, Token: token}
+	if err := c.SendDeleteRequest(ctx, "payments/paymentmethods/saved", idempotencyKey, query, nil); err != nil {
+		return recordSpanError(span, fmt.Errorf("failed to remove saved instrument: %w", err))
 	}
 	return nil
 }
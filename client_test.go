@@ -0,0 +1,59 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEmptyIdsRejectedLocally(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	// IDs are checked even with validation of request bodies turned off.
+	client, err := NewPayNowApiClient("key", "secret", server.URL, WithValidation(false))
+	if err != nil {
+		t.Fatalf("NewPayNowApiClient() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	cases := []struct {
+		name  string
+		field string
+		call  func() error
+	}{
+		{name: "GetPaymentStatus", field: "paymentId", call: func() error {
+			_, err := client.GetPaymentStatus(ctx, "")
+			return err
+		}},
+		{name: "CreateRefund", field: "paymentId", call: func() error {
+			_, err := client.CreateRefund(ctx, "", &CreateRefundRequest{Amount: 100}, "refund-1")
+			return err
+		}},
+		{name: "GetRefundStatus", field: "refundId", call: func() error {
+			_, err := client.GetRefundStatus(ctx, "")
+			return err
+		}},
+		{name: "CancelRefund", field: "refundId", call: func() error {
+			_, err := client.CancelRefund(ctx, "", "cancel-1")
+			return err
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if !errors.Is(err, ErrInvalidRequest) {
+				t.Fatalf("error = %v, want ErrInvalidRequest", err)
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) || len(errs.Field(tc.field)) != 1 || errs.Field(tc.field)[0].Code != ValidationCodeRequired {
+				t.Errorf("error = %v, want a required error for %s", err, tc.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package paynow_sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Base URLs of the Paynow environments, pass one of them to NewPayNowApiClient.
+const (
+	Production = "https://api.paynow.pl/v3/"
+	Sandbox    = "https://api.sandbox.paynow.pl/v3/"
+)
+
+var ErrInvalidBaseURL = errors.New("paynow: invalid base URL")
+
+// normalizeBaseURL checks that baseUrl is an absolute http(s) URL without query or fragment
+// and returns it with a trailing slash, so endpoints can be appended to it.
+func normalizeBaseURL(baseUrl string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(baseUrl))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidBaseURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("%w: %q: scheme must be http or https", ErrInvalidBaseURL, baseUrl)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("%w: %q: missing host", ErrInvalidBaseURL, baseUrl)
+	}
+	if parsed.User != nil || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("%w: %q: must not contain credentials, query or fragment", ErrInvalidBaseURL, baseUrl)
+	}
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+		if parsed.RawPath != "" {
+			parsed.RawPath += "/"
+		}
+	}
+	return parsed.String(), nil
+}
+
+// joinURL appends endpoint to a base URL returned by normalizeBaseURL.
+func joinURL(baseUrl, endpoint string) string {
+	return baseUrl + strings.TrimLeft(endpoint, "/")
+}
+
+// endpointPath joins path segments, escaping the identifiers placed between them.
+func endpointPath(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	return strings.Join(escaped, "/")
+}
@@ -0,0 +1,111 @@
+package paynow_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Error types returned by Paynow in ErrorResponse.Errors[].ErrorType.
+const (
+	ErrorTypeValidationError              = "VALIDATION_ERROR"
+	ErrorTypeUnauthorized                 = "UNAUTHORIZED"
+	ErrorTypeForbidden                    = "FORBIDDEN"
+	ErrorTypeNotFound                     = "NOT_FOUND"
+	ErrorTypePaymentMethodNotAvailable    = "PAYMENT_METHOD_NOT_AVAILABLE"
+	ErrorTypePaymentAmountTooSmall        = "PAYMENT_AMOUNT_TOO_SMALL"
+	ErrorTypePaymentAmountTooLarge        = "PAYMENT_AMOUNT_TOO_LARGE"
+	ErrorTypeInsufficientBalanceFunds     = "INSUFFICIENT_BALANCE_FUNDS"
+	ErrorTypeSystemTemporarilyUnavailable = "SYSTEM_TEMPORARILY_UNAVAILABLE"
+)
+
+// ErrInvalidRequest wraps the error returned by RequestType.Validate when a request fails local validation.
+var ErrInvalidRequest = errors.New("paynow: invalid request")
+
+// Sentinel errors matched by *APIError with errors.Is.
+var (
+	ErrValidation               = errors.New("paynow: validation error")
+	ErrUnauthorized             = errors.New("paynow: unauthorized")
+	ErrNotFound                 = errors.New("paynow: not found")         // Any resource, e.g. a refund or a saved instrument
+	ErrPaymentNotFound          = errors.New("paynow: payment not found") // Only for GetPaymentStatus and CreateRefund, see APIError.Operation
+	ErrPaymentMethodUnavailable = errors.New("paynow: payment method not available")
+)
+
+// APIError is returned when Paynow responds with a non-2xx status. Use errors.As to inspect it.
+type APIError struct {
+	Operation  string         // Endpoint method that failed, e.g. OperationGetPaymentStatus, or OperationSendRequest
+	StatusCode int            // HTTP status code of the response
+	Response   *ErrorResponse // Parsed error body, nil when the body is not a Paynow error response
+	Body       []byte         // Raw response body
+	Header     http.Header    // Response headers
+}
+
+// paymentOperations address a payment by its ID, a NOT_FOUND answered to them means the payment does not exist.
+var paymentOperations = []string{OperationGetPaymentStatus, OperationCreateRefund}
+
+func newAPIError(operation string, resp *APIResponse) *APIError {
+	apiErr := &APIError{
+		Operation:  operation,
+		StatusCode: resp.StatusCode,
+		Body:       resp.Body,
+		Header:     resp.Header,
+	}
+	errorResponse := &ErrorResponse{}
+	if err := json.Unmarshal(apiErr.Body, errorResponse); err == nil && (errorResponse.StatusCode != 0 || len(errorResponse.Errors) != 0) {
+		apiErr.Response = errorResponse
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Response == nil || len(e.Response.Errors) == 0 {
+		return fmt.Sprintf("paynow: status %d: %s", e.StatusCode, strings.TrimSpace(string(e.Body)))
+	}
+	messages := make([]string, 0, len(e.Response.Errors))
+	for _, responseError := range e.Response.Errors {
+		messages = append(messages, responseError.ErrorType+": "+responseError.Message)
+	}
+	return fmt.Sprintf("paynow: status %d: %s", e.StatusCode, strings.Join(messages, "; "))
+}
+
+// ErrorTypes returns the ErrorType of every error reported in the response.
+func (e *APIError) ErrorTypes() []string {
+	if e.Response == nil {
+		return nil
+	}
+	errorTypes := make([]string, 0, len(e.Response.Errors))
+	for _, responseError := range e.Response.Errors {
+		errorTypes = append(errorTypes, responseError.ErrorType)
+	}
+	return errorTypes
+}
+
+// HasErrorType reports whether the response contains an error of the given type.
+func (e *APIError) HasErrorType(errorType string) bool {
+	for _, t := range e.ErrorTypes() {
+		if t == errorType {
+			return true
+		}
+	}
+	return false
+}
+
+// Is matches the sentinel errors of this package by error type, falling back to the HTTP status.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return e.HasErrorType(ErrorTypeValidationError)
+	case ErrUnauthorized:
+		return e.HasErrorType(ErrorTypeUnauthorized) || e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.HasErrorType(ErrorTypeNotFound) || e.StatusCode == http.StatusNotFound
+	case ErrPaymentNotFound:
+		return slices.Contains(paymentOperations, e.Operation) && e.Is(ErrNotFound)
+	case ErrPaymentMethodUnavailable:
+		return e.HasErrorType(ErrorTypePaymentMethodNotAvailable)
+	}
+	return false
+}
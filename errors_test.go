@@ -0,0 +1,97 @@
+package paynow_sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	notFoundBody := []byte(`{"statusCode":404,"errors":[{"errorType":"NOT_FOUND","message":"not found"}]}`)
+	cases := []struct {
+		name       string
+		operation  string
+		statusCode int
+		body       []byte
+		matches    []error
+		mismatches []error
+	}{
+		{
+			name:       "payment status not found",
+			operation:  OperationGetPaymentStatus,
+			statusCode: http.StatusNotFound,
+			body:       notFoundBody,
+			matches:    []error{ErrNotFound, ErrPaymentNotFound},
+			mismatches: []error{ErrValidation, ErrUnauthorized},
+		},
+		{
+			name:       "refund of a missing payment",
+			operation:  OperationCreateRefund,
+			statusCode: http.StatusNotFound,
+			body:       []byte(`not found`),
+			matches:    []error{ErrNotFound, ErrPaymentNotFound},
+		},
+		{
+			name:       "refund status not found",
+			operation:  OperationGetRefundStatus,
+			statusCode: http.StatusNotFound,
+			body:       notFoundBody,
+			matches:    []error{ErrNotFound},
+			mismatches: []error{ErrPaymentNotFound},
+		},
+		{
+			name:       "saved instrument not found",
+			operation:  OperationRemoveSavedInstrument,
+			statusCode: http.StatusNotFound,
+			body:       notFoundBody,
+			matches:    []error{ErrNotFound},
+			mismatches: []error{ErrPaymentNotFound},
+		},
+		{
+			name:       "direct request not found",
+			operation:  OperationSendRequest,
+			statusCode: http.StatusNotFound,
+			matches:    []error{ErrNotFound},
+			mismatches: []error{ErrPaymentNotFound},
+		},
+		{
+			name:       "validation error",
+			operation:  OperationCreatePayment,
+			statusCode: http.StatusBadRequest,
+			body:       []byte(`{"statusCode":400,"errors":[{"errorType":"VALIDATION_ERROR","message":"amount"}]}`),
+			matches:    []error{ErrValidation},
+			mismatches: []error{ErrNotFound, ErrPaymentNotFound, ErrUnauthorized},
+		},
+		{
+			name:       "unauthorized by status",
+			operation:  OperationGetPaymentStatus,
+			statusCode: http.StatusUnauthorized,
+			matches:    []error{ErrUnauthorized},
+			mismatches: []error{ErrNotFound, ErrPaymentNotFound},
+		},
+		{
+			name:       "payment method unavailable",
+			operation:  OperationCreatePayment,
+			statusCode: http.StatusBadRequest,
+			body:       []byte(`{"statusCode":400,"errors":[{"errorType":"PAYMENT_METHOD_NOT_AVAILABLE","message":"BLIK"}]}`),
+			matches:    []error{ErrPaymentMethodUnavailable},
+			mismatches: []error{ErrValidation},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := fmt.Errorf("failed: %w", newAPIError(tc.operation, &APIResponse{StatusCode: tc.statusCode, Body: tc.body}))
+			for _, target := range tc.matches {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = false, want true", err, target)
+				}
+			}
+			for _, target := range tc.mismatches {
+				if errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = true, want false", err, target)
+				}
+			}
+		})
+	}
+}
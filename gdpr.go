@@ -0,0 +1,296 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrGDPRNoticesNotFound is returned by GDPRNoticeCache.Notices when Paynow has no notices for any of the locales tried.
+var ErrGDPRNoticesNotFound = errors.New("paynow: GDPR notices not found")
+
+const (
+	defaultGDPRCacheTTL       = time.Hour
+	defaultGDPRFallbackLocale = "en"
+)
+
+// GDPRNoticeCache keeps GDPR notices in memory for a TTL, per locale, and picks the best locale available.
+// It is safe for concurrent use.
+type GDPRNoticeCache struct {
+	api            PayNowApi
+	ttl            time.Duration
+	fallbackLocale string
+
+	mu      sync.Mutex
+	entries map[string]gdprCacheEntry // Keyed by lower-cased locale
+}
+
+type gdprCacheEntry struct {
+	notices   []GetGDPRClausesResponseItem
+	expiresAt time.Time
+}
+
+// NewGDPRNoticeCache caches notices fetched with api for ttl (1 hour when 0). fallbackLocale is tried last,
+// "en" when empty.
+func NewGDPRNoticeCache(api PayNowApi, ttl time.Duration, fallbackLocale string) *GDPRNoticeCache {
+	if ttl <= 0 {
+		ttl = defaultGDPRCacheTTL
+	}
+	if fallbackLocale == "" {
+		fallbackLocale = defaultGDPRFallbackLocale
+	}
+	return &GDPRNoticeCache{
+		api:            api,
+		ttl:            ttl,
+		fallbackLocale: fallbackLocale,
+		entries:        make(map[string]gdprCacheEntry),
+	}
+}
+
+// Notices returns the notices for the first locale from LocaleFallbacks(locale, fallbackLocale) that Paynow
+// has notices for, e.g. pl-PL, then pl, then en.
+func (c *GDPRNoticeCache) Notices(ctx context.Context, locale string) ([]GetGDPRClausesResponseItem, error) {
+	candidates := LocaleFallbacks(locale, c.fallbackLocale)
+	for _, candidate := range candidates {
+		notices, err := c.fetch(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if matching := noticesForLocale(notices, candidate); len(matching) != 0 {
+			return matching, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: tried %s", ErrGDPRNoticesNotFound, strings.Join(candidates, ", "))
+}
+
+// Invalidate drops all cached notices.
+func (c *GDPRNoticeCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+func (c *GDPRNoticeCache) fetch(ctx context.Context, locale string) ([]GetGDPRClausesResponseItem, error) {
+	key := strings.ToLower(locale)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.notices, nil
+	}
+	response, err := c.api.GetGDPRClauses(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+	var notices []GetGDPRClausesResponseItem
+	if response != nil {
+		notices = *response
+	}
+	c.mu.Lock()
+	c.entries[key] = gdprCacheEntry{notices: notices, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return notices, nil
+}
+
+// LocaleFallbacks returns locale followed by its less specific forms and fallbackLocale, without duplicates,
+// e.g. "pl-PL" gives [pl-PL pl en] for fallbackLocale "en". Underscores are accepted as separators.
+func LocaleFallbacks(locale, fallbackLocale string) []string {
+	var candidates []string
+	add := func(tag string) {
+		for _, candidate := range candidates {
+			if strings.EqualFold(candidate, tag) {
+				return
+			}
+		}
+		candidates = append(candidates, tag)
+	}
+	for _, tag := range []string{locale, fallbackLocale} {
+		tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+		for tag != "" {
+			add(tag)
+			index := strings.LastIndex(tag, "-")
+			if index < 0 {
+				break
+			}
+			tag = tag[:index]
+		}
+	}
+	return candidates
+}
+
+// noticesForLocale returns the notices in locale, a locale without a region also matches notices in any region
+// of that language, e.g. "en" matches "en-GB".
+func noticesForLocale(notices []GetGDPRClausesResponseItem, locale string) []GetGDPRClausesResponseItem {
+	var matching []GetGDPRClausesResponseItem
+	for _, notice := range notices {
+		noticeLocale := strings.ReplaceAll(notice.Locale, "_", "-")
+		if strings.EqualFold(noticeLocale, locale) || (!strings.Contains(locale, "-") && strings.HasPrefix(strings.ToLower(noticeLocale), strings.ToLower(locale)+"-")) {
+			matching = append(matching, notice)
+		}
+	}
+	return matching
+}
+
+// allowedGDPRTags are kept by SanitizedHTML, other tags are removed but their text is kept.
+var allowedGDPRTags = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.B: true, atom.Strong: true, atom.I: true, atom.Em: true, atom.U: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.A: true, atom.Span: true, atom.Div: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// droppedGDPRTags are removed together with their content.
+var droppedGDPRTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true, atom.Embed: true,
+	atom.Template: true, atom.Noscript: true, atom.Head: true, atom.Title: true, atom.Form: true,
+}
+
+// paragraphGDPRTags are separated by a blank line in PlainText, lineGDPRTags start a new line.
+var (
+	paragraphGDPRTags = map[atom.Atom]bool{
+		atom.P: true, atom.Div: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	}
+	lineGDPRTags = map[atom.Atom]bool{atom.Ul: true, atom.Ol: true, atom.Tr: true}
+)
+
+// SanitizedHTML returns Content limited to basic formatting tags, safe to embed in a page or an email.
+// Attributes are removed except http, https and mailto links.
+func (g GetGDPRClausesResponseItem) SanitizedHTML() string {
+	nodes, err := parseGDPRContent(g.Content)
+	if err != nil {
+		return html.EscapeString(g.Content)
+	}
+	var builder strings.Builder
+	for _, node := range nodes {
+		writeSanitized(&builder, node)
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// PlainText returns Content without markup, for plain text emails and receipts. Paragraphs are separated
+// by a blank line, list items start with "- " and links are followed by their URL.
+func (g GetGDPRClausesResponseItem) PlainText() string {
+	nodes, err := parseGDPRContent(g.Content)
+	if err != nil {
+		return g.Content
+	}
+	var builder strings.Builder
+	for _, node := range nodes {
+		writeText(&builder, node)
+	}
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(builder.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank = len(lines) != 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseGDPRContent(content string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(content), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+}
+
+func writeSanitized(builder *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		builder.WriteString(html.EscapeString(node.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if droppedGDPRTags[node.DataAtom] {
+		return
+	}
+	allowed := allowedGDPRTags[node.DataAtom]
+	if allowed {
+		builder.WriteString("<" + node.Data)
+		if href := safeHref(node); href != "" {
+			builder.WriteString(` href="` + html.EscapeString(href) + `" rel="noopener noreferrer nofollow"`)
+		}
+		builder.WriteString(">")
+		if node.DataAtom == atom.Br {
+			return
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeSanitized(builder, child)
+	}
+	if allowed {
+		builder.WriteString("</" + node.Data + ">")
+	}
+}
+
+func writeText(builder *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		builder.WriteString(node.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	switch {
+	case droppedGDPRTags[node.DataAtom]:
+		return
+	case node.DataAtom == atom.Br:
+		builder.WriteString("\n")
+		return
+	case node.DataAtom == atom.Li:
+		builder.WriteString("\n- ")
+	case paragraphGDPRTags[node.DataAtom]:
+		builder.WriteString("\n\n")
+	case lineGDPRTags[node.DataAtom]:
+		builder.WriteString("\n")
+	}
+	start := builder.Len()
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeText(builder, child)
+	}
+	if href := safeHref(node); href != "" && !strings.Contains(builder.String()[start:], strings.TrimPrefix(href, "mailto:")) {
+		builder.WriteString(" (" + strings.TrimPrefix(href, "mailto:") + ")")
+	}
+	switch {
+	case paragraphGDPRTags[node.DataAtom]:
+		builder.WriteString("\n\n")
+	case lineGDPRTags[node.DataAtom]:
+		builder.WriteString("\n")
+	}
+}
+
+// safeHref returns the href of a link if it uses the http, https or mailto scheme.
+func safeHref(node *html.Node) string {
+	if node.DataAtom != atom.A {
+		return ""
+	}
+	for _, attr := range node.Attr {
+		if attr.Namespace != "" || attr.Key != "href" {
+			continue
+		}
+		href := strings.TrimSpace(attr.Val)
+		parsed, err := url.Parse(href)
+		if err != nil {
+			return ""
+		}
+		switch strings.ToLower(parsed.Scheme) {
+		case "http", "https", "mailto":
+			return href
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,259 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGDPRSanitizedHTML(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "script and style removed with their content",
+			content: `<p>Hello</p><script>alert(1)</script><style>p{color:red}</style>`,
+			want:    `<p>Hello</p>`,
+		},
+		{
+			name:    "javascript href",
+			content: `<a href="javascript:alert(1)">bad</a>`,
+			want:    `<a>bad</a>`,
+		},
+		{
+			name:    "data href",
+			content: `<a href="data:text/html,<script>alert(1)</script>">data</a>`,
+			want:    `<a>data</a>`,
+		},
+		{
+			name:    "http and mailto hrefs kept",
+			content: `<a href="https://paynow.pl" target="_blank">site</a> <a href="mailto:iod@paynow.pl">mail</a>`,
+			want:    `<a href="https://paynow.pl" rel="noopener noreferrer nofollow">site</a> <a href="mailto:iod@paynow.pl" rel="noopener noreferrer nofollow">mail</a>`,
+		},
+		{
+			name:    "onclick and style attributes stripped",
+			content: `<p onclick="steal()" style="display:none">Hello <b class="x">world</b></p>`,
+			want:    `<p>Hello <b>world</b></p>`,
+		},
+		{
+			name:    "disallowed tags unwrapped",
+			content: `<table><tr><td>cell</td></tr></table><img src=x onerror=alert(1)>text`,
+			want:    `celltext`,
+		},
+		{
+			name:    "text escaped",
+			content: `Fish & chips <3 "quoted"`,
+			want:    `Fish &amp; chips &lt;3 &#34;quoted&#34;`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (GetGDPRClausesResponseItem{Content: tc.content}).SanitizedHTML(); got != tc.want {
+				t.Errorf("SanitizedHTML() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGDPRPlainText(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "paragraphs, lists and line breaks",
+			content: `<h1>Title</h1><p>First   paragraph.</p><ul><li>one</li><li>two</li></ul><p>Line one<br>Line two</p>`,
+			want:    "Title\n\nFirst paragraph.\n\n- one\n- two\n\nLine one\nLine two",
+		},
+		{
+			name:    "links followed by their URL unless the text shows it",
+			content: `<p>Contact <a href="mailto:iod@paynow.pl">iod@paynow.pl</a> or <a href="https://paynow.pl">our site</a>.</p>`,
+			want:    "Contact iod@paynow.pl or our site (https://paynow.pl).",
+		},
+		{
+			name:    "unsafe link without URL",
+			content: `<a href="javascript:alert(1)">click</a>`,
+			want:    "click",
+		},
+		{
+			name:    "script dropped and entities decoded",
+			content: `<p>Fish &amp; chips</p><script>alert(1)</script>`,
+			want:    "Fish & chips",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (GetGDPRClausesResponseItem{Content: tc.content}).PlainText(); got != tc.want {
+				t.Errorf("PlainText() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	cases := []struct {
+		locale, fallback string
+		want             []string
+	}{
+		{locale: "pl-PL", fallback: "en", want: []string{"pl-PL", "pl", "en"}},
+		{locale: "pl_PL", fallback: "en", want: []string{"pl-PL", "pl", "en"}},
+		{locale: "en-GB", fallback: "en", want: []string{"en-GB", "en"}},
+		{locale: "EN", fallback: "en", want: []string{"EN"}},
+		{locale: "", fallback: "en", want: []string{"en"}},
+		{locale: "zh-Hant-TW", fallback: "en-US", want: []string{"zh-Hant-TW", "zh-Hant", "zh", "en-US", "en"}},
+	}
+	for _, tc := range cases {
+		if got := LocaleFallbacks(tc.locale, tc.fallback); !slices.Equal(got, tc.want) {
+			t.Errorf("LocaleFallbacks(%q, %q) = %q, want %q", tc.locale, tc.fallback, got, tc.want)
+		}
+	}
+}
+
+// fakeGDPRApi serves notices per requested locale and records the locales asked for.
+type fakeGDPRApi struct {
+	PayNowApi
+	notices map[string][]GetGDPRClausesResponseItem
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeGDPRApi) GetGDPRClauses(_ context.Context, locale string) (*[]GetGDPRClausesResponseItem, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, locale)
+	notices := f.notices[locale]
+	return &notices, nil
+}
+
+func (f *fakeGDPRApi) takeCalls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := f.calls
+	f.calls = nil
+	return calls
+}
+
+func TestGDPRNoticeCacheFallsBack(t *testing.T) {
+	english := GetGDPRClausesResponseItem{Title: "Information clause", Content: "<p>mElements S.A.</p>", Locale: "en-GB"}
+	polish := GetGDPRClausesResponseItem{Title: "Klauzula informacyjna", Content: "<p>mElements S.A.</p>", Locale: "pl-PL"}
+	cases := []struct {
+		name      string
+		notices   map[string][]GetGDPRClausesResponseItem
+		want      []GetGDPRClausesResponseItem
+		wantCalls []string
+	}{
+		{
+			name:      "exact locale",
+			notices:   map[string][]GetGDPRClausesResponseItem{"pl-PL": {polish}},
+			want:      []GetGDPRClausesResponseItem{polish},
+			wantCalls: []string{"pl-PL"},
+		},
+		{
+			name:      "language",
+			notices:   map[string][]GetGDPRClausesResponseItem{"pl": {polish}},
+			want:      []GetGDPRClausesResponseItem{polish},
+			wantCalls: []string{"pl-PL", "pl"},
+		},
+		{
+			name: "fallback locale in another region",
+			// Paynow answers with notices of other locales when it has none in the requested one.
+			notices:   map[string][]GetGDPRClausesResponseItem{"pl-PL": {english}, "pl": {english}, "en": {english}},
+			want:      []GetGDPRClausesResponseItem{english},
+			wantCalls: []string{"pl-PL", "pl", "en"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			api := &fakeGDPRApi{notices: tc.notices}
+			cache := NewGDPRNoticeCache(api, time.Hour, "")
+			notices, err := cache.Notices(context.Background(), "pl-PL")
+			if err != nil {
+				t.Fatalf("Notices() error = %v", err)
+			}
+			if !slices.Equal(notices, tc.want) {
+				t.Errorf("Notices() = %+v, want %+v", notices, tc.want)
+			}
+			if calls := api.takeCalls(); !slices.Equal(calls, tc.wantCalls) {
+				t.Errorf("fetched locales %q, want %q", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGDPRNoticeCacheNotFound(t *testing.T) {
+	api := &fakeGDPRApi{}
+	cache := NewGDPRNoticeCache(api, time.Hour, "en")
+	if _, err := cache.Notices(context.Background(), "pl-PL"); !errors.Is(err, ErrGDPRNoticesNotFound) {
+		t.Fatalf("Notices() error = %v, want ErrGDPRNoticesNotFound", err)
+	}
+	if calls := api.takeCalls(); !slices.Equal(calls, []string{"pl-PL", "pl", "en"}) {
+		t.Errorf("fetched locales %q, want [pl-PL pl en]", calls)
+	}
+}
+
+func TestGDPRNoticeCacheTTL(t *testing.T) {
+	polish := GetGDPRClausesResponseItem{Title: "Klauzula informacyjna", Content: "<p>mElements S.A.</p>", Locale: "pl-PL"}
+	api := &fakeGDPRApi{notices: map[string][]GetGDPRClausesResponseItem{"pl": {polish}}}
+	cache := NewGDPRNoticeCache(api, time.Hour, "en")
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := cache.Notices(ctx, "pl-PL"); err != nil {
+			t.Fatalf("Notices() error = %v", err)
+		}
+	}
+	if calls := api.takeCalls(); !slices.Equal(calls, []string{"pl-PL", "pl"}) {
+		t.Errorf("fetched locales %q, want [pl-PL pl] once, the second call served from the cache", calls)
+	}
+	// Locales are cached case-insensitively.
+	if _, err := cache.Notices(ctx, "PL-pl"); err != nil {
+		t.Fatalf("Notices() error = %v", err)
+	}
+	if calls := api.takeCalls(); len(calls) != 0 {
+		t.Errorf("fetched locales %q for PL-pl, want none", calls)
+	}
+
+	cache.mu.Lock()
+	for key, entry := range cache.entries {
+		entry.expiresAt = time.Now().Add(-time.Second)
+		cache.entries[key] = entry
+	}
+	cache.mu.Unlock()
+	if _, err := cache.Notices(ctx, "pl-PL"); err != nil {
+		t.Fatalf("Notices() error = %v", err)
+	}
+	if calls := api.takeCalls(); !slices.Equal(calls, []string{"pl-PL", "pl"}) {
+		t.Errorf("fetched locales %q after the TTL, want [pl-PL pl]", calls)
+	}
+
+	cache.Invalidate()
+	if _, err := cache.Notices(ctx, "pl-PL"); err != nil {
+		t.Fatalf("Notices() error = %v", err)
+	}
+	if calls := api.takeCalls(); !slices.Equal(calls, []string{"pl-PL", "pl"}) {
+		t.Errorf("fetched locales %q after Invalidate, want [pl-PL pl]", calls)
+	}
+}
+
+func TestGDPRNoticeCacheExpiresAfterTTL(t *testing.T) {
+	api := &fakeGDPRApi{notices: map[string][]GetGDPRClausesResponseItem{"en": {{Title: "Clause", Locale: "en"}}}}
+	cache := NewGDPRNoticeCache(api, 10*time.Millisecond, "en")
+	ctx := context.Background()
+	if _, err := cache.Notices(ctx, "en"); err != nil {
+		t.Fatalf("Notices() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := cache.Notices(ctx, "en"); err != nil {
+		t.Fatalf("Notices() error = %v", err)
+	}
+	if calls := api.takeCalls(); !slices.Equal(calls, []string{"en", "en"}) {
+		t.Errorf("fetched locales %q, want [en en] with the entry expired in between", calls)
+	}
+}
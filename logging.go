@@ -0,0 +1,125 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders are replaced with redactedValue before headers are logged.
+var sensitiveHeaders = []string{"Api-Key", "Signature"}
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range sensitiveHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redactedValue)
+		}
+	}
+	return header
+}
+
+func redactString(value string) slog.Value {
+	if value == "" {
+		return slog.StringValue("")
+	}
+	return slog.StringValue(redactedValue)
+}
+
+func (c *PayNowApiClient) logRequest(ctx context.Context, request *APIRequest, requestObj RequestType) {
+	if !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("endpoint", request.Endpoint),
+		slog.String("idempotency_key", request.Header.Get("Idempotency-Key")),
+		slog.Any("header", redactHeader(request.Header)),
+	}
+	if requestObj != nil {
+		attrs = append(attrs, slog.Any("request", requestObj))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "paynow request", attrs...)
+}
+
+func (c *PayNowApiClient) logResponse(ctx context.Context, request *APIRequest, resp *APIResponse, latency time.Duration, err error) {
+	attrs := []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("endpoint", request.Endpoint),
+		slog.String("idempotency_key", request.Header.Get("Idempotency-Key")),
+		slog.Duration("latency", latency),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err == nil {
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "paynow response", attrs...)
+		return
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		attrs = append(attrs, slog.Any("error_types", apiErr.ErrorTypes()))
+	}
+	attrs = append(attrs, slog.String("error", err.Error()))
+	c.logger.LogAttrs(ctx, slog.LevelError, "paynow request failed", attrs...)
+}
+
+// LogValue keeps the API key and signature secret out of logs.
+func (c *PayNowApiClient) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("base_url", c.baseUrl),
+		slog.Any("api_key", redactString(c.apiKey)),
+		slog.Any("secret", redactString(c.secret)),
+	)
+}
+
+// LogValue redacts the buyer's personal data: name, email, phone and address.
+func (b *BuyerInfo) LogValue() slog.Value {
+	if b == nil {
+		return slog.AnyValue(nil)
+	}
+	return slog.GroupValue(
+		slog.Any("email", redactString(b.Email)),
+		slog.Any("firstName", redactString(b.FirstName)),
+		slog.Any("lastName", redactString(b.LastName)),
+		slog.Bool("phone", b.Phone != nil),
+		slog.Bool("address", b.Address != nil),
+		slog.String("locale", b.Locale),
+		slog.String("externalId", b.ExternalId),
+	)
+}
+
+// LogValue logs the payment without buyer personal data, see BuyerInfo.LogValue, the BLIK code and the saved card token.
+func (c *CreatePaymentRequest) LogValue() slog.Value {
+	if c == nil {
+		return slog.AnyValue(nil)
+	}
+	return slog.GroupValue(
+		slog.Int64("amount", c.Amount),
+		slog.String("currency", c.Currency),
+		slog.String("externalId", c.ExternalId),
+		slog.String("description", c.Description),
+		slog.Any("buyer", c.Buyer),
+		slog.Int("orderItems", len(c.OrderItems)),
+		slog.String("continueUrl", c.ContinueUrl),
+		slog.Int64("validityTime", c.ValidityTime),
+		slog.Int64("paymentMethodId", c.PaymentMethodId),
+		slog.Any("authorizationCode", redactString(c.AuthorizationCode)),
+		slog.Any("paymentMethodToken", redactString(c.PaymentMethodToken)),
+	)
+}
+
+// LogValue redacts the saved card token.
+func (r *RemoveSavedInstrumentQuery) LogValue() slog.Value {
+	if r == nil {
+		return slog.AnyValue(nil)
+	}
+	return slog.GroupValue(
+		slog.String("externalBuyerId", r.ExternalBuyerId),
+		slog.Any("token", redactString(r.Token)),
+	)
+}
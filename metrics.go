@@ -0,0 +1,80 @@
+package paynow_sdk
+
+import (
+	"context"
+	"time"
+)
+
+// Results reported in NotificationMetrics.Result.
+const (
+	NotificationResultProcessed        = "processed"
+	NotificationResultMethodNotAllowed = "method_not_allowed"
+	NotificationResultBodyTooLarge     = "body_too_large"
+	NotificationResultReadError        = "read_error"
+	NotificationResultMissingSignature = "missing_signature"
+	NotificationResultInvalidSignature = "invalid_signature"
+	NotificationResultMalformed        = "malformed"
+	NotificationResultRejected         = "rejected"
+	NotificationResultCallbackError    = "callback_error"
+)
+
+// RequestMetrics describes a finished call to the Paynow API.
+type RequestMetrics struct {
+	Operation  string        // Endpoint method name, e.g. OperationCreatePayment, or OperationSendRequest for direct Send*Request calls
+	Method     string        // HTTP method
+	StatusCode int           // HTTP status, 0 when no response was received
+	ErrorTypes []string      // Paynow error types of an error response
+	Err        error         // Error returned to the caller, nil on success
+	Duration   time.Duration // Time spent on the call, retries included
+}
+
+// NotificationMetrics describes a notification handled by NotificationHandler.
+type NotificationMetrics struct {
+	Result     string // One of the NotificationResult* constants
+	StatusCode int    // HTTP status answered to Paynow
+	Duration   time.Duration
+}
+
+// MetricsHook receives measurements of API calls and notifications, see paynowprom for a Prometheus implementation.
+// Methods are called synchronously and must be safe for concurrent use.
+type MetricsHook interface {
+	ObserveRequest(RequestMetrics)
+	ObserveNotification(NotificationMetrics)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(RequestMetrics)           {}
+func (noopMetrics) ObserveNotification(NotificationMetrics) {}
+
+// WithMetrics reports every API call to hook.
+func WithMetrics(hook MetricsHook) Option {
+	return func(o *clientOptions) {
+		if hook != nil {
+			o.metrics = hook
+		}
+	}
+}
+
+// WithNotificationMetrics reports every handled notification to hook.
+func WithNotificationMetrics(hook MetricsHook) NotificationHandlerOption {
+	return func(h *NotificationHandler) {
+		if hook != nil {
+			h.metrics = hook
+		}
+	}
+}
+
+type operationKey struct{}
+
+// withOperation stores the endpoint method name for the instrumentation of the underlying request.
+func withOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+func operationFromContext(ctx context.Context, fallback string) string {
+	if operation, ok := ctx.Value(operationKey{}).(string); ok {
+		return operation
+	}
+	return fallback
+}
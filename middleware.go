@@ -0,0 +1,73 @@
+package paynow_sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// APIRequest is a signed request about to be sent to the Paynow API.
+// Changing the body or query after signing invalidates the Signature header.
+type APIRequest struct {
+	Operation string      // Endpoint method name, e.g. OperationCreatePayment, or OperationSendRequest for direct Send*Request calls
+	Method    string      // HTTP method
+	Endpoint  string      // Path relative to the base URL, e.g. "payments"
+	URL       string      // Base URL joined with Endpoint
+	Header    http.Header // Content-Type, Api-Key, Idempotency-Key and Signature
+	Query     url.Values
+	Body      []byte // Empty for GET requests
+}
+
+// APIResponse is a raw response of the Paynow API. Error statuses are turned into *APIError after the middleware chain.
+type APIResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Handler sends an APIRequest. It returns an error only when no response was received.
+type Handler func(ctx context.Context, req *APIRequest) (*APIResponse, error)
+
+// Middleware wraps a Handler. It may change the request, inspect or replace the response,
+// or return without calling next to short-circuit the call.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middlewares to the client. The first one is the outermost and sees the request first.
+// Middlewares run once per endpoint method call, retries happen further down inside next.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *clientOptions) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+func chain(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			handler = middlewares[i](handler)
+		}
+	}
+	return handler
+}
+
+// execute is the innermost Handler, it sends the request with the resty client.
+func (c *PayNowApiClient) execute(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	request := c.httpClient.R().
+		SetContext(ctx).
+		SetHeaderMultiValues(req.Header).
+		SetQueryParamsFromValues(req.Query)
+	if len(req.Body) != 0 {
+		request.SetBody(req.Body)
+	}
+	if c.retryPolicy != nil {
+		c.retryPolicy.attach(request, req.Operation, c.rateLimiter)
+	}
+	resp, err := request.Execute(req.Method, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &APIResponse{
+		StatusCode: resp.StatusCode(),
+		Header:     resp.Header(),
+		Body:       resp.Bytes(),
+	}, nil
+}
@@ -1,8 +1,119 @@
 package paynow_sdk
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"time"
+)
+
 type Notification struct {
-	PaymentId  string `json:"paymentId"`            // Unique identifier for the payment
-	ExternalId string `json:"externalId,omitempty"` // Unique identifier for the payment in the merchant's system
-	Status     string `json:"status"`               // Status of the payment, Possible values: [NEW, PENDING, ERROR, COMPLETED, CANCELED]
-	ModifiedAt string `json:"modifiedAt"`           // Timestamp of the last modification in ISO 8601 format
+	PaymentId  string        `json:"paymentId"`            // Unique identifier for the payment
+	ExternalId string        `json:"externalId,omitempty"` // Unique identifier for the payment in the merchant's system
+	Status     PaymentStatus `json:"status"`               // Status of the payment, see the PaymentStatus constants
+	ModifiedAt string        `json:"modifiedAt"`           // Timestamp of the last modification in ISO 8601 format
+}
+
+const defaultNotificationBodyLimit = 64 << 10
+
+// ErrNotificationRejected can be returned (or wrapped) by a NotificationCallback to answer 400 Bad Request
+// for a notification that will never be processable. Any other error is answered with 500 so Paynow retries.
+var ErrNotificationRejected = errors.New("paynow: notification rejected")
+
+// NotificationCallback processes a notification whose signature was verified.
+type NotificationCallback func(ctx context.Context, notification *Notification) error
+
+// NotificationHandler is an http.Handler receiving Paynow payment status notifications.
+type NotificationHandler struct {
+	signatureKey string
+	callback     NotificationCallback
+	bodyLimit    int64
+	tracer       trace.Tracer
+	metrics      MetricsHook
+}
+
+type NotificationHandlerOption func(*NotificationHandler)
+
+// WithNotificationBodyLimit sets the maximum accepted body size in bytes, 64 KiB by default.
+func WithNotificationBodyLimit(limit int64) NotificationHandlerOption {
+	return func(h *NotificationHandler) {
+		h.bodyLimit = limit
+	}
+}
+
+// NewNotificationHandler returns a handler that verifies the Signature header against the raw request body
+// using signatureKey, decodes the Notification and passes it to callback.
+//
+// Responses: 200 when callback succeeds, 400 for an invalid signature, malformed body or ErrNotificationRejected,
+// 405 for methods other than POST, 413 for a body over the limit and 500 for any other callback error.
+// Paynow retries delivery for every non-2xx response.
+func NewNotificationHandler(signatureKey string, callback NotificationCallback, opts ...NotificationHandlerOption) *NotificationHandler {
+	h := &NotificationHandler{
+		signatureKey: signatureKey,
+		callback:     callback,
+		bodyLimit:    defaultNotificationBodyLimit,
+		tracer:       noopTracer(),
+		metrics:      noopMetrics{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx, span := h.tracer.Start(r.Context(), "Paynow notification", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	statusCode, result, err := h.handle(ctx, w, r, span)
+	span.SetAttributes(AttributeHTTPStatusCode.Int(statusCode))
+	h.metrics.ObserveNotification(NotificationMetrics{Result: result, StatusCode: statusCode, Duration: time.Since(start)})
+	if err != nil {
+		recordSpanError(span, err)
+		if statusCode == http.StatusMethodNotAllowed {
+			w.Header().Set("Allow", http.MethodPost)
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	w.WriteHeader(statusCode)
+}
+
+// handle processes the notification and returns the HTTP status to answer with, one of the NotificationResult*
+// constants and, on failure, the error to report.
+func (h *NotificationHandler) handle(ctx context.Context, w http.ResponseWriter, r *http.Request, span trace.Span) (int, string, error) {
+	if r.Method != http.MethodPost {
+		return http.StatusMethodNotAllowed, NotificationResultMethodNotAllowed, errors.New("method not allowed")
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.bodyLimit))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return http.StatusRequestEntityTooLarge, NotificationResultBodyTooLarge, errors.New("request body too large")
+		}
+		return http.StatusBadRequest, NotificationResultReadError, errors.New("failed to read request body")
+	}
+	signature := r.Header.Get("Signature")
+	if signature == "" {
+		return http.StatusBadRequest, NotificationResultMissingSignature, errors.New("missing signature")
+	}
+	if valid, err := ConfirmNotificationSignature(h.signatureKey, body, signature); err != nil || !valid {
+		return http.StatusBadRequest, NotificationResultInvalidSignature, errors.New("invalid signature")
+	}
+	notification := &Notification{}
+	if err := json.Unmarshal(body, notification); err != nil {
+		return http.StatusBadRequest, NotificationResultMalformed, errors.New("malformed notification")
+	}
+	span.SetAttributes(AttributePaymentId.String(notification.PaymentId), AttributeStatus.String(string(notification.Status)))
+	if err := h.callback(ctx, notification); err != nil {
+		span.RecordError(err)
+		if errors.Is(err, ErrNotificationRejected) {
+			return http.StatusBadRequest, NotificationResultRejected, errors.New("notification rejected")
+		}
+		return http.StatusInternalServerError, NotificationResultCallbackError, errors.New("failed to process notification")
+	}
+	return http.StatusOK, NotificationResultProcessed, nil
 }
@@ -0,0 +1,162 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testSignatureKey = "notification-key"
+
+const testNotificationBody = `{"paymentId":"NOLV-8F9-08K-WGD","externalId":"order-1","status":"CONFIRMED","modifiedAt":"2024-05-01T12:00:00"}`
+
+// notificationMetrics records the results reported by a NotificationHandler.
+type notificationMetrics struct {
+	noopMetrics
+	results []NotificationMetrics
+}
+
+func (m *notificationMetrics) ObserveNotification(observed NotificationMetrics) {
+	m.results = append(m.results, observed)
+}
+
+func TestNotificationHandler(t *testing.T) {
+	cases := []struct {
+		name        string
+		method      string
+		body        string
+		signature   string // Signature header, "" leaves it out
+		callbackErr error
+		wantStatus  int
+		wantResult  string
+		wantCalled  bool
+	}{
+		{
+			name:       "valid signature",
+			method:     http.MethodPost,
+			body:       testNotificationBody,
+			signature:  SignNotification(testSignatureKey, []byte(testNotificationBody)),
+			wantStatus: http.StatusOK,
+			wantResult: NotificationResultProcessed,
+			wantCalled: true,
+		},
+		{
+			name:       "missing signature",
+			method:     http.MethodPost,
+			body:       testNotificationBody,
+			wantStatus: http.StatusBadRequest,
+			wantResult: NotificationResultMissingSignature,
+		},
+		{
+			name:       "signature not in base64",
+			method:     http.MethodPost,
+			body:       testNotificationBody,
+			signature:  "not base64!",
+			wantStatus: http.StatusBadRequest,
+			wantResult: NotificationResultInvalidSignature,
+		},
+		{
+			name:       "signature of another key",
+			method:     http.MethodPost,
+			body:       testNotificationBody,
+			signature:  SignNotification("other-key", []byte(testNotificationBody)),
+			wantStatus: http.StatusBadRequest,
+			wantResult: NotificationResultInvalidSignature,
+		},
+		{
+			name:       "signature of another body",
+			method:     http.MethodPost,
+			body:       testNotificationBody,
+			signature:  SignNotification(testSignatureKey, []byte(strings.Replace(testNotificationBody, "CONFIRMED", "REJECTED", 1))),
+			wantStatus: http.StatusBadRequest,
+			wantResult: NotificationResultInvalidSignature,
+		},
+		{
+			name:       "body over the limit",
+			method:     http.MethodPost,
+			body:       testNotificationBody + strings.Repeat(" ", 1024),
+			signature:  SignNotification(testSignatureKey, []byte(testNotificationBody+strings.Repeat(" ", 1024))),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantResult: NotificationResultBodyTooLarge,
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			body:       `{"paymentId":`,
+			signature:  SignNotification(testSignatureKey, []byte(`{"paymentId":`)),
+			wantStatus: http.StatusBadRequest,
+			wantResult: NotificationResultMalformed,
+		},
+		{
+			name:       "GET",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantResult: NotificationResultMethodNotAllowed,
+		},
+		{
+			name:       "PUT",
+			method:     http.MethodPut,
+			body:       testNotificationBody,
+			signature:  SignNotification(testSignatureKey, []byte(testNotificationBody)),
+			wantStatus: http.StatusMethodNotAllowed,
+			wantResult: NotificationResultMethodNotAllowed,
+		},
+		{
+			name:        "rejected by callback",
+			method:      http.MethodPost,
+			body:        testNotificationBody,
+			signature:   SignNotification(testSignatureKey, []byte(testNotificationBody)),
+			callbackErr: fmt.Errorf("unknown order: %w", ErrNotificationRejected),
+			wantStatus:  http.StatusBadRequest,
+			wantResult:  NotificationResultRejected,
+			wantCalled:  true,
+		},
+		{
+			name:        "callback error",
+			method:      http.MethodPost,
+			body:        testNotificationBody,
+			signature:   SignNotification(testSignatureKey, []byte(testNotificationBody)),
+			callbackErr: errors.New("database unavailable"),
+			wantStatus:  http.StatusInternalServerError,
+			wantResult:  NotificationResultCallbackError,
+			wantCalled:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received *Notification
+			metrics := &notificationMetrics{}
+			handler := NewNotificationHandler(testSignatureKey, func(_ context.Context, notification *Notification) error {
+				received = notification
+				return tc.callbackErr
+			}, WithNotificationBodyLimit(int64(len(testNotificationBody))), WithNotificationMetrics(metrics))
+
+			request := httptest.NewRequest(tc.method, "/paynow/notifications", strings.NewReader(tc.body))
+			if tc.signature != "" {
+				request.Header.Set("Signature", tc.signature)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+			if allow := recorder.Header().Get("Allow"); tc.wantStatus == http.StatusMethodNotAllowed && allow != http.MethodPost {
+				t.Errorf("Allow = %q, want %q", allow, http.MethodPost)
+			}
+			if len(metrics.results) != 1 || metrics.results[0].Result != tc.wantResult || metrics.results[0].StatusCode != tc.wantStatus {
+				t.Errorf("observed %+v, want one %s result with status %d", metrics.results, tc.wantResult, tc.wantStatus)
+			}
+			if called := received != nil; called != tc.wantCalled {
+				t.Fatalf("callback called = %t, want %t", called, tc.wantCalled)
+			}
+			if tc.wantCalled && (received.PaymentId != "NOLV-8F9-08K-WGD" || received.Status != PaymentStatusConfirmed) {
+				t.Errorf("callback got %+v, want payment NOLV-8F9-08K-WGD with status CONFIRMED", received)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package paynow_sdk
+
+import (
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultTimeout   = 30 * time.Second
+	defaultUserAgent = "paynow-gosdk"
+)
+
+type clientOptions struct {
+	httpClient     *http.Client
+	transport      http.RoundTripper
+	timeout        time.Duration
+	userAgent      string
+	proxyURL       string
+	retryPolicy    *RetryPolicy
+	validate       bool
+	logger         *slog.Logger
+	tracer         trace.Tracer
+	metrics        MetricsHook
+	middlewares    []Middleware
+	rateLimit      *RateLimitPolicy
+	circuitBreaker *CircuitBreaker
+}
+
+// Option configures a PayNowApiClient created with NewPayNowApiClient.
+type Option func(*clientOptions)
+
+func defaultClientOptions() *clientOptions {
+	return &clientOptions{
+		timeout:   defaultTimeout,
+		userAgent: defaultUserAgent,
+		validate:  true,
+		logger:    slog.New(slog.DiscardHandler),
+		tracer:    noopTracer(),
+		metrics:   noopMetrics{},
+	}
+}
+
+// WithHTTPClient makes the client send requests through a copy of the given *http.Client instead of a default one.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for every request, e.g. a tuned *http.Transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout sets the timeout of a single request, 0 disables it. Defaults to 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithProxy routes requests through the given proxy URL, e.g. "http://proxy:8080". The transport must be
+// an *http.Transport, it is copied before the proxy is set. NewPayNowApiClient returns an error wrapping
+// ErrInvalidProxy for a malformed URL or another transport type.
+func WithProxy(proxyURL string) Option {
+	return func(o *clientOptions) {
+		o.proxyURL = proxyURL
+	}
+}
+
+// ErrInvalidProxy is returned by NewPayNowApiClient when the proxy set with WithProxy cannot be used.
+var ErrInvalidProxy = errors.New("paynow: invalid proxy")
+
+// proxyTransport returns a copy of transport sending requests through proxyURL. A nil transport stands
+// for http.DefaultTransport.
+func proxyTransport(transport http.RoundTripper, proxyURL string) (*http.Transport, error) {
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxy, err)
+	}
+	if proxy.Scheme == "" || proxy.Host == "" {
+		return nil, fmt.Errorf("%w: %q: missing scheme or host", ErrInvalidProxy, proxyURL)
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	httpTransport, ok := transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("%w: transport %T is not an *http.Transport", ErrInvalidProxy, transport)
+	}
+	httpTransport = httpTransport.Clone()
+	httpTransport.Proxy = http.ProxyURL(proxy)
+	return httpTransport, nil
+}
+
+// WithValidation controls whether requests are validated with their Validate method before being signed and sent.
+// Validation is enabled by default, an invalid request fails with ErrInvalidRequest without a network round trip.
+func WithValidation(enabled bool) Option {
+	return func(o *clientOptions) {
+		o.validate = enabled
+	}
+}
+
+// WithLogger logs every request and response to logger: requests at debug level, responses at info level and
+// failures at error level. Credentials, signatures and buyer personal data are redacted. Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *clientOptions) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package paynow_sdk
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPaymentMethodCatalogTTL      = 5 * time.Minute
+	defaultPaymentMethodCatalogMaxStale = time.Hour
+	paymentMethodRefreshTimeout         = 30 * time.Second
+)
+
+// PaymentMethods is the response of GetPaymentMethods with lookup helpers. Values returned by PaymentMethodCatalog
+// are shared by all callers and must not be modified.
+type PaymentMethods struct {
+	FetchedAt time.Time // When the methods were fetched from Paynow
+	groups    []GetPaymentMethodsResponse
+}
+
+// NewPaymentMethods wraps a response of GetPaymentMethods, e.g. one fetched with ExternalBuyerId, which
+// PaymentMethodCatalog does not cache.
+func NewPaymentMethods(methods []GetPaymentMethodsResponse) *PaymentMethods {
+	return &PaymentMethods{FetchedAt: time.Now(), groups: methods}
+}
+
+// All returns the methods as returned by Paynow, including DISABLED ones.
+func (p *PaymentMethods) All() []GetPaymentMethodsResponse {
+	return p.groups
+}
+
+// Enabled returns the ENABLED methods grouped by type. Types without ENABLED methods are left out.
+func (p *PaymentMethods) Enabled() []GetPaymentMethodsResponse {
+	var groups []GetPaymentMethodsResponse
+	for _, group := range p.groups {
+		if methods := enabledPaymentMethods(group.PaymentMethods); len(methods) != 0 {
+			groups = append(groups, GetPaymentMethodsResponse{Type: group.Type, PaymentMethods: methods})
+		}
+	}
+	return groups
+}
+
+// ById returns the method with the given ID, e.g. to check the method chosen by the buyer before CreatePayment.
+// The method may be DISABLED, see PaymentMethod.Enabled.
+func (p *PaymentMethods) ById(id int64) (PaymentMethod, bool) {
+	for _, group := range p.groups {
+		for _, method := range group.PaymentMethods {
+			if method.Id == id {
+				return method, true
+			}
+		}
+	}
+	return PaymentMethod{}, false
+}
+
+// ByType returns the ENABLED methods of the given type, e.g. the banks of PaymentMethodTypePbl.
+func (p *PaymentMethods) ByType(methodType string) []PaymentMethod {
+	var methods []PaymentMethod
+	for _, group := range p.groups {
+		if group.Type == methodType {
+			methods = append(methods, enabledPaymentMethods(group.PaymentMethods)...)
+		}
+	}
+	return methods
+}
+
+// Find returns the first ENABLED method of the given type, see FindPaymentMethod.
+func (p *PaymentMethods) Find(methodType string) (PaymentMethod, bool) {
+	return FindPaymentMethod(p.groups, methodType)
+}
+
+// Grouped returns the ENABLED methods with one group per type, for rendering a checkout page. Groups of the types
+// in typeOrder come first, in that order, followed by the other types in the order returned by Paynow.
+func (p *PaymentMethods) Grouped(typeOrder ...string) []GetPaymentMethodsResponse {
+	var groups []GetPaymentMethodsResponse
+	for _, group := range p.Enabled() {
+		index := slices.IndexFunc(groups, func(g GetPaymentMethodsResponse) bool { return g.Type == group.Type })
+		if index < 0 {
+			groups = append(groups, group)
+			continue
+		}
+		groups[index].PaymentMethods = append(groups[index].PaymentMethods, group.PaymentMethods...)
+	}
+	rank := func(methodType string) int {
+		if index := slices.Index(typeOrder, methodType); index >= 0 {
+			return index
+		}
+		return len(typeOrder)
+	}
+	slices.SortStableFunc(groups, func(a, b GetPaymentMethodsResponse) int {
+		return rank(a.Type) - rank(b.Type)
+	})
+	return groups
+}
+
+func enabledPaymentMethods(methods []PaymentMethod) []PaymentMethod {
+	var enabled []PaymentMethod
+	for _, method := range methods {
+		if method.Enabled() {
+			enabled = append(enabled, method)
+		}
+	}
+	return enabled
+}
+
+// PaymentMethodCatalogOptions configure a PaymentMethodCatalog.
+type PaymentMethodCatalogOptions struct {
+	TTL             time.Duration                                  // How long methods are served without asking Paynow, 5 minutes when 0
+	MaxStale        time.Duration                                  // How long after TTL methods are still served while refreshed in the background, 1 hour when 0
+	ApplePayEnabled bool                                           // Passed as GetPaymentMethodsQuery.ApplePayEnabled
+	OnRefreshError  func(amount int64, currency string, err error) // Called when a background refresh fails, optional
+}
+
+// PaymentMethodCatalog keeps the payment methods in memory per amount and currency, so checkout pages do not
+// call Paynow on every render. Methods older than TTL are still returned and refreshed in the background;
+// only the first request for an amount and currency, or one for methods older than TTL+MaxStale, waits for Paynow.
+// Saved cards depend on the buyer and are not cached, call GetPaymentMethods with ExternalBuyerId for them.
+// It is safe for concurrent use.
+type PaymentMethodCatalog struct {
+	api     PayNowApi
+	options PaymentMethodCatalogOptions
+
+	mu         sync.Mutex
+	entries    map[paymentMethodsKey]*PaymentMethods
+	refreshing map[paymentMethodsKey]bool // Keys with a background refresh in progress
+}
+
+type paymentMethodsKey struct {
+	amount   int64
+	currency string
+}
+
+// NewPaymentMethodCatalog creates a catalog of the methods fetched with api.
+func NewPaymentMethodCatalog(api PayNowApi, options PaymentMethodCatalogOptions) *PaymentMethodCatalog {
+	if options.TTL <= 0 {
+		options.TTL = defaultPaymentMethodCatalogTTL
+	}
+	if options.MaxStale <= 0 {
+		options.MaxStale = defaultPaymentMethodCatalogMaxStale
+	}
+	return &PaymentMethodCatalog{
+		api:        api,
+		options:    options,
+		entries:    make(map[paymentMethodsKey]*PaymentMethods),
+		refreshing: make(map[paymentMethodsKey]bool),
+	}
+}
+
+// Methods returns the payment methods available for a payment of amount (in the smallest currency unit)
+// in currency.
+func (c *PaymentMethodCatalog) Methods(ctx context.Context, amount int64, currency string) (*PaymentMethods, error) {
+	key := paymentMethodsKey{amount: amount, currency: currency}
+	c.mu.Lock()
+	methods, ok := c.entries[key]
+	if ok {
+		age := time.Since(methods.FetchedAt)
+		if age >= c.options.TTL && age < c.options.TTL+c.options.MaxStale && !c.refreshing[key] {
+			c.refreshing[key] = true
+			go c.refreshInBackground(context.WithoutCancel(ctx), key)
+		}
+		if age < c.options.TTL+c.options.MaxStale {
+			c.mu.Unlock()
+			return methods, nil
+		}
+	}
+	c.mu.Unlock()
+	return c.fetch(ctx, key)
+}
+
+// Refresh fetches the methods for amount and currency from Paynow and caches them, e.g. to warm up the catalog
+// with the most common basket amounts on startup.
+func (c *PaymentMethodCatalog) Refresh(ctx context.Context, amount int64, currency string) (*PaymentMethods, error) {
+	return c.fetch(ctx, paymentMethodsKey{amount: amount, currency: currency})
+}
+
+// Invalidate drops all cached methods.
+func (c *PaymentMethodCatalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+func (c *PaymentMethodCatalog) fetch(ctx context.Context, key paymentMethodsKey) (*PaymentMethods, error) {
+	response, err := c.api.GetPaymentMethods(ctx, &GetPaymentMethodsQuery{
+		Amount:          key.amount,
+		Currency:        key.currency,
+		ApplePayEnabled: c.options.ApplePayEnabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var groups []GetPaymentMethodsResponse
+	if response != nil {
+		groups = *response
+	}
+	methods := NewPaymentMethods(groups)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Baskets of many different amounts would otherwise keep entries that are never served again.
+	for entryKey, entry := range c.entries {
+		if time.Since(entry.FetchedAt) >= c.options.TTL+c.options.MaxStale {
+			delete(c.entries, entryKey)
+		}
+	}
+	c.entries[key] = methods
+	return methods, nil
+}
+
+func (c *PaymentMethodCatalog) refreshInBackground(ctx context.Context, key paymentMethodsKey) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.refreshing, key)
+		c.mu.Unlock()
+	}()
+	ctx, cancel := context.WithTimeout(ctx, paymentMethodRefreshTimeout)
+	defer cancel()
+	if _, err := c.fetch(ctx, key); err != nil && c.options.OnRefreshError != nil {
+		c.options.OnRefreshError(key.amount, key.currency, err)
+	}
+}
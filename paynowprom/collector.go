@@ -0,0 +1,105 @@
+// Package paynowprom exposes Paynow client and notification metrics to Prometheus.
+package paynowprom
+
+import (
+	"errors"
+	"github.com/Hkozacz/paynow-gosdk"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+)
+
+// Collector implements paynow_sdk.MetricsHook and prometheus.Collector. Register it with a prometheus.Registerer
+// and pass it to paynow_sdk.WithMetrics and paynow_sdk.WithNotificationMetrics.
+type Collector struct {
+	requests      *prometheus.CounterVec
+	requestErrors *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	notifications *prometheus.CounterVec
+	notifyLatency prometheus.Histogram
+}
+
+var _ paynow_sdk.MetricsHook = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates the metrics under the given namespace, e.g. "shop" gives "shop_paynow_requests_total".
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "paynow",
+			Name:      "requests_total",
+			Help:      "Paynow API calls by endpoint method and HTTP status, status is 0 when no response was received.",
+		}, []string{"operation", "method", "status"}),
+		requestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "paynow",
+			Name:      "request_errors_total",
+			Help:      "Failed Paynow API calls by endpoint method and Paynow error type.",
+		}, []string{"operation", "error_type"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "paynow",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Paynow API calls by endpoint method, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "paynow",
+			Name:      "notifications_total",
+			Help:      "Handled Paynow notifications by result, e.g. processed or invalid_signature.",
+		}, []string{"result"}),
+		notifyLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "paynow",
+			Name:      "notification_duration_seconds",
+			Help:      "Time spent handling Paynow notifications, callback included.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+}
+
+func (c *Collector) ObserveRequest(m paynow_sdk.RequestMetrics) {
+	c.requests.WithLabelValues(m.Operation, m.Method, strconv.Itoa(m.StatusCode)).Inc()
+	c.latency.WithLabelValues(m.Operation).Observe(m.Duration.Seconds())
+	if m.Err == nil {
+		return
+	}
+	if len(m.ErrorTypes) == 0 {
+		c.requestErrors.WithLabelValues(m.Operation, errorType(m)).Inc()
+		return
+	}
+	for _, t := range m.ErrorTypes {
+		c.requestErrors.WithLabelValues(m.Operation, t).Inc()
+	}
+}
+
+// errorType labels failures that carry no Paynow error type.
+func errorType(m paynow_sdk.RequestMetrics) string {
+	var apiErr *paynow_sdk.APIError
+	if m.StatusCode == 0 && !errors.As(m.Err, &apiErr) {
+		return "NETWORK_ERROR"
+	}
+	return "HTTP_" + strconv.Itoa(m.StatusCode)
+}
+
+func (c *Collector) ObserveNotification(m paynow_sdk.NotificationMetrics) {
+	c.notifications.WithLabelValues(m.Result).Inc()
+	c.notifyLatency.Observe(m.Duration.Seconds())
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.requestErrors.Describe(ch)
+	c.latency.Describe(ch)
+	c.notifications.Describe(ch)
+	c.notifyLatency.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.requestErrors.Collect(ch)
+	c.latency.Collect(ch)
+	c.notifications.Collect(ch)
+	c.notifyLatency.Collect(ch)
+}
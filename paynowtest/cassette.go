@@ -0,0 +1,303 @@
+package paynowtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// redactedHeaders are never written to a cassette.
+var redactedHeaders = []string{"Api-Key", "Signature"}
+
+// redactedBuyerFields are replaced in every "buyer" object of a recorded JSON body.
+var redactedBuyerFields = []string{"email", "firstName", "lastName", "phone", "address"}
+
+// redactedPaymentFields are replaced in a recorded JSON body and redactedQueryParams in a recorded query,
+// they authorize a payment or identify a saved card.
+var (
+	redactedPaymentFields = []string{"authorizationCode", "paymentMethodToken"}
+	redactedQueryParams   = []string{"token"}
+)
+
+// redactedSavedInstrumentFields are replaced in every "savedInstruments" entry of a recorded response body.
+var redactedSavedInstrumentFields = []string{"token"}
+
+// Interaction is a recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"` // Encoded with sorted keys
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is the file format written by Recorder and read by Replayer.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that forwards requests to the real API and stores every interaction in a cassette
+// file, with Api-Key, Signature, buyer personal data, BLIK codes and saved card tokens redacted.
+// Plug it in with paynow_sdk.WithTransport.
+type Recorder struct {
+	path     string
+	next     http.RoundTripper
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder records interactions to the file at path, sending requests through next (http.DefaultTransport when nil).
+// The file is rewritten after every interaction.
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{path: path, next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("paynowtest: failed to read request body: %w", err)
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("paynowtest: failed to read response body: %w", err)
+	}
+	interaction := Interaction{
+		Request: recordRequest(req, requestBody),
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       redactResponseBody(responseBody),
+		},
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("paynowtest: failed to marshal cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return fmt.Errorf("paynowtest: failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Replayer is an http.RoundTripper serving responses from a cassette file without network access.
+// Requests are matched by method, path, query and normalized body; each recorded interaction is served once, in order.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the cassette file at path.
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("paynowtest: failed to read cassette: %w", err)
+	}
+	cassette := Cassette{}
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("paynowtest: failed to parse cassette: %w", err)
+	}
+	return &Replayer{interactions: cassette.Interactions, used: make([]bool, len(cassette.Interactions))}, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("paynowtest: failed to read request body: %w", err)
+	}
+	recorded := recordRequest(req, requestBody)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("paynowtest: no recorded interaction for %s %s?%s", recorded.Method, recorded.Path, recorded.Query)
+}
+
+func matches(recorded, actual RecordedRequest) bool {
+	return recorded.Method == actual.Method &&
+		recorded.Path == actual.Path &&
+		recorded.Query == actual.Query &&
+		normalizeBody(recorded.Body) == normalizeBody(actual.Body)
+}
+
+func recordRequest(req *http.Request, body []byte) RecordedRequest {
+	query := req.URL.Query()
+	for _, name := range redactedQueryParams {
+		if query.Has(name) {
+			query.Set(name, redacted)
+		}
+	}
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  query.Encode(),
+		Header: redactHeader(req.Header),
+		Body:   redactBody(body),
+	}
+}
+
+// readBody reads the whole body and replaces it with an in-memory copy.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+func redactBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	if fields, ok := value.(map[string]any); ok {
+		for _, field := range redactedPaymentFields {
+			if _, ok := fields[field]; ok {
+				fields[field] = redacted
+			}
+		}
+	}
+	redactBuyer(value)
+	data, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+func redactBuyer(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if buyer, ok := nested.(map[string]any); ok && key == "buyer" {
+				for _, field := range redactedBuyerFields {
+					if _, ok := buyer[field]; ok {
+						buyer[field] = redacted
+					}
+				}
+			}
+			redactBuyer(nested)
+		}
+	case []any:
+		for _, nested := range v {
+			redactBuyer(nested)
+		}
+	}
+}
+
+func redactResponseBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	redactSavedInstruments(value)
+	data, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+func redactSavedInstruments(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if instruments, ok := nested.([]any); ok && key == "savedInstruments" {
+				for _, instrument := range instruments {
+					fields, ok := instrument.(map[string]any)
+					if !ok {
+						continue
+					}
+					for _, field := range redactedSavedInstrumentFields {
+						if _, ok := fields[field]; ok {
+							fields[field] = redacted
+						}
+					}
+				}
+			}
+			redactSavedInstruments(nested)
+		}
+	case []any:
+		for _, nested := range v {
+			redactSavedInstruments(nested)
+		}
+	}
+}
+
+// normalizeBody re-encodes JSON bodies so that formatting and key order do not affect matching.
+func normalizeBody(body string) string {
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return strings.TrimSpace(body)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return strings.TrimSpace(body)
+	}
+	return string(data)
+}
@@ -0,0 +1,171 @@
+package paynowtest
+
+import (
+	"context"
+	"github.com/Hkozacz/paynow-gosdk"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testBuyerId     = "buyer-1"
+	testCardToken   = "9f1c2b7e-4d0a-4a8e-b1f3-card-token"
+	testBlikCode    = "777123"
+	testBuyerEmail  = "jan.kowalski@example.com"
+	testBuyerStreet = "Marszałkowska"
+	testBuyerPhone  = 601234567
+)
+
+func testBuyer() *paynow_sdk.BuyerInfo {
+	return &paynow_sdk.BuyerInfo{
+		Email:      testBuyerEmail,
+		FirstName:  "Jan",
+		LastName:   "Kowalski",
+		Phone:      &paynow_sdk.Phone{Prefix: "+48", Number: testBuyerPhone},
+		Address:    &paynow_sdk.Address{Billing: &paynow_sdk.AddressType{Street: testBuyerStreet, Zipcode: "00-123", City: "Warszawa", Country: "PL"}},
+		ExternalId: testBuyerId,
+	}
+}
+
+// runCassetteScenario makes calls whose requests and responses carry every kind of redacted value and returns
+// the IDs of the created payments.
+func runCassetteScenario(t *testing.T, client *paynow_sdk.PayNowApiClient) []string {
+	t.Helper()
+	ctx := context.Background()
+	methods, err := client.GetPaymentMethods(ctx, &paynow_sdk.GetPaymentMethodsQuery{Amount: 1000, Currency: "PLN", ExternalBuyerId: testBuyerId})
+	if err != nil {
+		t.Fatalf("GetPaymentMethods() error = %v", err)
+	}
+	var savedTokens []string
+	for _, group := range *methods {
+		for _, method := range group.PaymentMethods {
+			for _, instrument := range method.SavedInstruments {
+				savedTokens = append(savedTokens, instrument.Token)
+			}
+		}
+	}
+	if len(savedTokens) != 1 {
+		t.Fatalf("GetPaymentMethods() returned saved tokens %v, want one", savedTokens)
+	}
+	cardPayment, err := client.CreatePayment(ctx, &paynow_sdk.CreatePaymentRequest{
+		Amount:             1000,
+		ExternalId:         "order-1",
+		Description:        "Order 1",
+		Buyer:              testBuyer(),
+		PaymentMethodToken: testCardToken,
+	}, "order-1")
+	if err != nil {
+		t.Fatalf("CreatePayment() with a saved card error = %v", err)
+	}
+	blikPayment, err := client.CreatePayment(ctx, &paynow_sdk.CreatePaymentRequest{
+		Amount:            1000,
+		ExternalId:        "order-2",
+		Description:       "Order 2",
+		Buyer:             testBuyer(),
+		PaymentMethodId:   2007,
+		AuthorizationCode: testBlikCode,
+	}, "order-2")
+	if err != nil {
+		t.Fatalf("CreatePayment() with BLIK error = %v", err)
+	}
+	if err := client.RemoveSavedInstrument(ctx, testBuyerId, testCardToken, "remove-card-1"); err != nil {
+		t.Fatalf("RemoveSavedInstrument() error = %v", err)
+	}
+	return []string{cardPayment.PaymentId, blikPayment.PaymentId}
+}
+
+func TestCassetteRoundTrip(t *testing.T) {
+	server := NewServer("cassette-api-key", "cassette-signature-key")
+	defer server.Close()
+	server.SetSavedInstruments(testBuyerId, []paynow_sdk.SavedInstrument{
+		{Name: "**** **** **** 1234", ExpirationDate: "12/27", Brand: "VISA", Status: paynow_sdk.SavedInstrumentStatusActive, Token: testCardToken},
+	})
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	var (
+		mu         sync.Mutex
+		signatures []string
+	)
+	captureSignatures := func(next paynow_sdk.Handler) paynow_sdk.Handler {
+		return func(ctx context.Context, req *paynow_sdk.APIRequest) (*paynow_sdk.APIResponse, error) {
+			mu.Lock()
+			signatures = append(signatures, req.Header.Get("Signature"))
+			mu.Unlock()
+			return next(ctx, req)
+		}
+	}
+	recordingClient := server.Client(paynow_sdk.WithTransport(NewRecorder(path, nil)), paynow_sdk.WithMiddleware(captureSignatures))
+	recorded := runCassetteScenario(t, recordingClient)
+	recordingClient.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cassette := string(data)
+	secrets := map[string]string{
+		"Api-Key":                 server.APIKey,
+		"buyer email":             testBuyerEmail,
+		"buyer phone":             strconv.Itoa(testBuyerPhone),
+		"buyer address":           testBuyerStreet,
+		"buyer last name":         "Kowalski",
+		"authorizationCode":       testBlikCode,
+		"paymentMethodToken":      testCardToken, // Also the token query parameter and savedInstruments[].token
+		"URL-encoded saved token": strings.ReplaceAll(testCardToken, "-", "%2D"),
+	}
+	for i, signature := range signatures {
+		secrets["Signature "+strconv.Itoa(i+1)] = signature
+	}
+	for name, secret := range secrets {
+		if secret != "" && strings.Contains(cassette, secret) {
+			t.Errorf("cassette contains the %s %q", name, secret)
+		}
+	}
+	if !strings.Contains(cassette, `\"savedInstruments\"`) || !strings.Contains(cassette, "token="+redacted) {
+		t.Errorf("cassette does not contain the redacted saved instruments and token query:\n%s", cassette)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	// A different secret changes every Signature, requests must still match the redacted cassette.
+	replayingClient, err := paynow_sdk.NewPayNowApiClient("other-api-key", "other-signature-key", server.BaseURL(),
+		paynow_sdk.WithTransport(replayer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replayingClient.Close()
+	server.Close()
+	replayed := runCassetteScenario(t, replayingClient)
+	for i := range recorded {
+		if replayed[i] != recorded[i] {
+			t.Errorf("replayed payment %d = %s, want %s", i+1, replayed[i], recorded[i])
+		}
+	}
+
+	// Every interaction is served once.
+	if _, err := replayingClient.GetPaymentMethods(context.Background(), &paynow_sdk.GetPaymentMethodsQuery{Amount: 1000, Currency: "PLN", ExternalBuyerId: testBuyerId}); err == nil {
+		t.Error("GetPaymentMethods() replayed twice, want an error for the used interaction")
+	}
+}
+
+func TestReplayerRejectsUnrecordedRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, []byte(`{"interactions":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	request, _ := http.NewRequest(http.MethodGet, "https://api.paynow.pl/v3/payments/P1/status", nil)
+	if _, err := replayer.RoundTrip(request); err == nil {
+		t.Error("RoundTrip() error = nil, want an error for an unrecorded request")
+	}
+}
@@ -0,0 +1,141 @@
+package paynowtest
+
+import (
+	"context"
+	"errors"
+	"github.com/Hkozacz/paynow-gosdk"
+	"sync"
+)
+
+// ErrNotScripted is returned by MockClient methods whose Func field is not set.
+var ErrNotScripted = errors.New("paynowtest: mock method not scripted")
+
+// Call is a single recorded invocation of a MockClient method.
+type Call struct {
+	Method string // Name of the invoked method, e.g. "CreatePayment"
+	Args   []any  // Arguments in declaration order, without the context
+}
+
+// MockClient is a paynow_sdk.PayNowApi implementation for unit tests. Each method records the call and delegates
+// to the matching Func field, returning ErrNotScripted when it is nil.
+type MockClient struct {
+	CreatePaymentFunc         func(ctx context.Context, body *paynow_sdk.CreatePaymentRequest, idempotencyKey string) (*paynow_sdk.CreatePaymentResponse, error)
+	GetPaymentStatusFunc      func(ctx context.Context, paymentId string) (*paynow_sdk.GetPaymentStatusResponse, error)
+	GetPaymentMethodsFunc     func(ctx context.Context, queryParameters *paynow_sdk.GetPaymentMethodsQuery) (*[]paynow_sdk.GetPaymentMethodsResponse, error)
+	GetGDPRClausesFunc        func(ctx context.Context, locale string) (*[]paynow_sdk.GetGDPRClausesResponseItem, error)
+	CreateRefundFunc          func(ctx context.Context, paymentId string, body *paynow_sdk.CreateRefundRequest, idempotencyKey string) (*paynow_sdk.CreateRefundResponse, error)
+	GetRefundStatusFunc       func(ctx context.Context, refundId string) (*paynow_sdk.GetRefundStatusResponse, error)
+	CancelRefundFunc          func(ctx context.Context, refundId string, idempotencyKey string) (*paynow_sdk.GetRefundStatusResponse, error)
+	PatchShopURLsFunc         func(ctx context.Context, bodyObj *paynow_sdk.PatchShopURLsRequest, idempotencyKey string) error
+	RemoveSavedInstrumentFunc func(ctx context.Context, externalBuyerId, token string, idempotencyKey string) error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ paynow_sdk.PayNowApi = (*MockClient)(nil)
+
+// Calls returns every recorded call in order.
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls of the given method in order.
+func (m *MockClient) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls, scripted functions are kept.
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *MockClient) record(method string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func (m *MockClient) CreatePayment(ctx context.Context, body *paynow_sdk.CreatePaymentRequest, idempotencyKey string) (*paynow_sdk.CreatePaymentResponse, error) {
+	m.record("CreatePayment", body, idempotencyKey)
+	if m.CreatePaymentFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.CreatePaymentFunc(ctx, body, idempotencyKey)
+}
+
+func (m *MockClient) GetPaymentStatus(ctx context.Context, paymentId string) (*paynow_sdk.GetPaymentStatusResponse, error) {
+	m.record("GetPaymentStatus", paymentId)
+	if m.GetPaymentStatusFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.GetPaymentStatusFunc(ctx, paymentId)
+}
+
+func (m *MockClient) GetPaymentMethods(ctx context.Context, queryParameters *paynow_sdk.GetPaymentMethodsQuery) (*[]paynow_sdk.GetPaymentMethodsResponse, error) {
+	m.record("GetPaymentMethods", queryParameters)
+	if m.GetPaymentMethodsFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.GetPaymentMethodsFunc(ctx, queryParameters)
+}
+
+func (m *MockClient) GetGDPRClauses(ctx context.Context, locale string) (*[]paynow_sdk.GetGDPRClausesResponseItem, error) {
+	m.record("GetGDPRClauses", locale)
+	if m.GetGDPRClausesFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.GetGDPRClausesFunc(ctx, locale)
+}
+
+func (m *MockClient) CreateRefund(ctx context.Context, paymentId string, body *paynow_sdk.CreateRefundRequest, idempotencyKey string) (*paynow_sdk.CreateRefundResponse, error) {
+	m.record("CreateRefund", paymentId, body, idempotencyKey)
+	if m.CreateRefundFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.CreateRefundFunc(ctx, paymentId, body, idempotencyKey)
+}
+
+func (m *MockClient) GetRefundStatus(ctx context.Context, refundId string) (*paynow_sdk.GetRefundStatusResponse, error) {
+	m.record("GetRefundStatus", refundId)
+	if m.GetRefundStatusFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.GetRefundStatusFunc(ctx, refundId)
+}
+
+func (m *MockClient) CancelRefund(ctx context.Context, refundId string, idempotencyKey string) (*paynow_sdk.GetRefundStatusResponse, error) {
+	m.record("CancelRefund", refundId, idempotencyKey)
+	if m.CancelRefundFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return m.CancelRefundFunc(ctx, refundId, idempotencyKey)
+}
+
+func (m *MockClient) PatchShopURLs(ctx context.Context, bodyObj *paynow_sdk.PatchShopURLsRequest, idempotencyKey string) error {
+	m.record("PatchShopURLs", bodyObj, idempotencyKey)
+	if m.PatchShopURLsFunc == nil {
+		return ErrNotScripted
+	}
+	return m.PatchShopURLsFunc(ctx, bodyObj, idempotencyKey)
+}
+
+func (m *MockClient) RemoveSavedInstrument(ctx context.Context, externalBuyerId, token string, idempotencyKey string) error {
+	m.record("RemoveSavedInstrument", externalBuyerId, token, idempotencyKey)
+	if m.RemoveSavedInstrumentFunc == nil {
+		return ErrNotScripted
+	}
+	return m.RemoveSavedInstrumentFunc(ctx, externalBuyerId, token, idempotencyKey)
+}
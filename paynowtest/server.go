@@ -0,0 +1,622 @@
+// Package paynowtest provides an in-memory fake of the Paynow v3 API for tests.
+package paynowtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/Hkozacz/paynow-gosdk"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Payment is the state the fake server keeps for a created payment.
+type Payment struct {
+	Id             string
+	IdempotencyKey string
+	Request        paynow_sdk.CreatePaymentRequest
+	Status         paynow_sdk.PaymentStatus
+	Refunds        []string
+}
+
+// Refund is the state the fake server keeps for a created refund.
+type Refund struct {
+	Id             string
+	PaymentId      string
+	IdempotencyKey string
+	Request        paynow_sdk.CreateRefundRequest
+	Status         paynow_sdk.RefundStatus
+}
+
+// Server is a fake Paynow API served by an httptest.Server. Requests are authenticated with the Api-Key header
+// and the Signature header is verified exactly like paynow_sdk.GenerateV3 computes it.
+type Server struct {
+	*httptest.Server
+	APIKey       string
+	SignatureKey string
+
+	mu               sync.Mutex
+	sequence         int
+	payments         map[string]*Payment
+	refunds          map[string]*Refund
+	idempotency      map[string]string
+	paymentMethods   []paynow_sdk.GetPaymentMethodsResponse
+	gdprNotices      []paynow_sdk.GetGDPRClausesResponseItem
+	savedInstruments map[string][]paynow_sdk.SavedInstrument // Keyed by external buyer ID
+	notificationUrl  string
+	continueUrl      string
+	failures         int // Number of upcoming requests answered with failureStatus, see FailNextRequests
+	failureStatus    int
+}
+
+// NewServer starts a fake Paynow API accepting the given credentials. Call Close when done.
+func NewServer(apiKey, signatureKey string) *Server {
+	s := &Server{
+		APIKey:           apiKey,
+		SignatureKey:     signatureKey,
+		payments:         make(map[string]*Payment),
+		refunds:          make(map[string]*Refund),
+		idempotency:      make(map[string]string),
+		paymentMethods:   DefaultPaymentMethods(),
+		gdprNotices:      DefaultGDPRNotices(),
+		savedInstruments: make(map[string][]paynow_sdk.SavedInstrument),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/payments", s.createPayment)
+	mux.HandleFunc("GET /v3/payments/{paymentId}/status", s.getPaymentStatus)
+	mux.HandleFunc("GET /v3/payments/paymentmethods", s.getPaymentMethods)
+	mux.HandleFunc("GET /v3/payments/dataprocessing/notices", s.getGDPRNotices)
+	mux.HandleFunc("POST /v3/payments/{paymentId}/refunds", s.createRefund)
+	mux.HandleFunc("GET /v3/refunds/{refundId}/status", s.getRefundStatus)
+	mux.HandleFunc("POST /v3/refunds/{refundId}/cancel", s.cancelRefund)
+	mux.HandleFunc("PATCH /v3/configuration/shop/urls", s.patchShopURLs)
+	mux.HandleFunc("DELETE /v3/payments/paymentmethods/saved", s.removeSavedInstrument)
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// BaseURL returns the base URL to pass to paynow_sdk.NewPayNowApiClient.
+func (s *Server) BaseURL() string {
+	return s.URL + "/v3/"
+}
+
+// Client returns a client configured with the server credentials and base URL.
+// It panics if the client cannot be created, which only happens for an invalid base URL.
+func (s *Server) Client(opts ...paynow_sdk.Option) *paynow_sdk.PayNowApiClient {
+	client, err := paynow_sdk.NewPayNowApiClient(s.APIKey, s.SignatureKey, s.BaseURL(), opts...)
+	if err != nil {
+		panic(fmt.Sprintf("paynowtest: failed to create client: %v", err))
+	}
+	return client
+}
+
+// DefaultPaymentMethods returns the payment methods served until SetPaymentMethods is called.
+// APPLE_PAY is served only when the request has applePayEnabled=true.
+func DefaultPaymentMethods() []paynow_sdk.GetPaymentMethodsResponse {
+	return []paynow_sdk.GetPaymentMethodsResponse{
+		{Type: "BLIK", PaymentMethods: []paynow_sdk.PaymentMethod{
+			{Id: 2007, Name: "BLIK", Description: "BLIK", Status: "ENABLED", AuthorizationType: paynow_sdk.AuthorizationTypeCode},
+		}},
+		{Type: "PBL", PaymentMethods: []paynow_sdk.PaymentMethod{
+			{Id: 2001, Name: "mTransfer", Description: "mBank", Status: "ENABLED", AuthorizationType: paynow_sdk.AuthorizationTypeRedirect},
+			{Id: 2003, Name: "Pekao24", Description: "Bank Pekao", Status: "DISABLED", AuthorizationType: paynow_sdk.AuthorizationTypeRedirect},
+		}},
+		{Type: "CARD", PaymentMethods: []paynow_sdk.PaymentMethod{
+			{Id: 2002, Name: "Card", Description: "Payment card", Status: "ENABLED", AuthorizationType: paynow_sdk.AuthorizationTypeRedirect},
+		}},
+		{Type: "GOOGLE_PAY", PaymentMethods: []paynow_sdk.PaymentMethod{
+			{Id: 2010, Name: "Google Pay", Description: "Google Pay", Status: "ENABLED", AuthorizationType: paynow_sdk.AuthorizationTypeCode},
+		}},
+		{Type: "APPLE_PAY", PaymentMethods: []paynow_sdk.PaymentMethod{
+			{Id: 2011, Name: "Apple Pay", Description: "Apple Pay", Status: "ENABLED", AuthorizationType: paynow_sdk.AuthorizationTypeCode},
+		}},
+	}
+}
+
+// DefaultGDPRNotices returns the GDPR notices served until SetGDPRNotices is called.
+func DefaultGDPRNotices() []paynow_sdk.GetGDPRClausesResponseItem {
+	return []paynow_sdk.GetGDPRClausesResponseItem{
+		{Title: "Klauzula informacyjna", Content: "<p>Administratorem danych jest mElements S.A.</p>", Locale: "pl-PL"},
+		{Title: "Information clause", Content: "<p>The data controller is mElements S.A.</p>", Locale: "en-GB"},
+	}
+}
+
+// FailNextRequests makes the next count authenticated requests fail with statusCode, e.g. 503 or 429, without
+// reaching the endpoint, to test how callers deal with transient Paynow errors.
+func (s *Server) FailNextRequests(count, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = count
+	s.failureStatus = statusCode
+}
+
+// SetPaymentMethods replaces the payment methods returned by GET payments/paymentmethods.
+func (s *Server) SetPaymentMethods(methods []paynow_sdk.GetPaymentMethodsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paymentMethods = methods
+}
+
+// SetSavedInstruments replaces the cards saved by the buyer. They are returned with the CARD methods
+// when GET payments/paymentmethods is called with the buyer's externalBuyerId.
+func (s *Server) SetSavedInstruments(externalBuyerId string, instruments []paynow_sdk.SavedInstrument) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.savedInstruments[externalBuyerId] = instruments
+}
+
+// SavedInstruments returns the cards currently saved by the buyer.
+func (s *Server) SavedInstruments(externalBuyerId string) []paynow_sdk.SavedInstrument {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]paynow_sdk.SavedInstrument(nil), s.savedInstruments[externalBuyerId]...)
+}
+
+// SetGDPRNotices replaces the notices returned by GET payments/dataprocessing/notices.
+func (s *Server) SetGDPRNotices(notices []paynow_sdk.GetGDPRClausesResponseItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gdprNotices = notices
+}
+
+// SetNotificationURL sets the URL notifications are sent to, the same as PATCH configuration/shop/urls does.
+func (s *Server) SetNotificationURL(notificationUrl string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notificationUrl = notificationUrl
+}
+
+// ShopURLs returns the notification and continue URLs configured through PatchShopURLs or SetNotificationURL.
+func (s *Server) ShopURLs() (notificationUrl, continueUrl string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notificationUrl, s.continueUrl
+}
+
+// Payment returns a copy of the stored payment.
+func (s *Server) Payment(paymentId string) (Payment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payment, ok := s.payments[paymentId]
+	if !ok {
+		return Payment{}, false
+	}
+	return *payment, true
+}
+
+// Refund returns a copy of the stored refund.
+func (s *Server) Refund(refundId string) (Refund, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refund, ok := s.refunds[refundId]
+	if !ok {
+		return Refund{}, false
+	}
+	return *refund, true
+}
+
+// SetPaymentStatus moves a payment to the given status. Transitions are not checked, so tests can force any state.
+func (s *Server) SetPaymentStatus(paymentId string, status paynow_sdk.PaymentStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payment, ok := s.payments[paymentId]
+	if !ok {
+		return fmt.Errorf("payment %s not found", paymentId)
+	}
+	payment.Status = status
+	return nil
+}
+
+// SetRefundStatus moves a refund to the given status. Transitions are not checked.
+func (s *Server) SetRefundStatus(refundId string, status paynow_sdk.RefundStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refund, ok := s.refunds[refundId]
+	if !ok {
+		return fmt.Errorf("refund %s not found", refundId)
+	}
+	refund.Status = status
+	return nil
+}
+
+// SendNotification posts a signed notification with the current payment status to the configured notification URL
+// and returns an error when the receiver does not answer with a 2xx status.
+func (s *Server) SendNotification(ctx context.Context, paymentId string) error {
+	s.mu.Lock()
+	payment, ok := s.payments[paymentId]
+	notificationUrl := s.notificationUrl
+	var notification paynow_sdk.Notification
+	if ok {
+		notification = paynow_sdk.Notification{
+			PaymentId:  payment.Id,
+			ExternalId: payment.Request.ExternalId,
+			Status:     payment.Status,
+			ModifiedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("payment %s not found", paymentId)
+	}
+	if notificationUrl == "" {
+		return fmt.Errorf("notification URL is not configured")
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notificationUrl, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Signature", paynow_sdk.SignNotification(s.SignatureKey, body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification rejected with status %s", resp.Status)
+	}
+	return nil
+}
+
+// UpdatePaymentStatus sets the payment status and sends the matching notification.
+func (s *Server) UpdatePaymentStatus(ctx context.Context, paymentId string, status paynow_sdk.PaymentStatus) error {
+	if err := s.SetPaymentStatus(paymentId, status); err != nil {
+		return err
+	}
+	return s.SendNotification(ctx, paymentId)
+}
+
+func (s *Server) nextId(prefix string) string {
+	s.sequence++
+	return fmt.Sprintf("%s-%06d", prefix, s.sequence)
+}
+
+func (s *Server) takeFailure() (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures == 0 {
+		return 0, false
+	}
+	s.failures--
+	return s.failureStatus, true
+}
+
+// authenticate checks the Api-Key header and verifies the Signature header over the raw body and query parameters.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, "failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if r.Header.Get("Api-Key") != s.APIKey {
+			writeError(w, http.StatusUnauthorized, paynow_sdk.ErrorTypeUnauthorized, "invalid Api-Key")
+			return
+		}
+		parameters := make(map[string]string)
+		for key, values := range r.URL.Query() {
+			parameters[key] = values[0]
+		}
+		signature, err := paynow_sdk.GenerateV3(s.APIKey, s.SignatureKey, r.Header.Get("Idempotency-Key"), string(body), parameters)
+		if err != nil || signature != r.Header.Get("Signature") {
+			writeError(w, http.StatusUnauthorized, paynow_sdk.ErrorTypeUnauthorized, "invalid Signature")
+			return
+		}
+		if r.Method != http.MethodGet && r.Header.Get("Idempotency-Key") == "" {
+			writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, "missing Idempotency-Key")
+			return
+		}
+		if statusCode, fail := s.takeFailure(); fail {
+			writeError(w, statusCode, paynow_sdk.ErrorTypeSystemTemporarilyUnavailable, "failure injected with FailNextRequests")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) createPayment(w http.ResponseWriter, r *http.Request) {
+	request := paynow_sdk.CreatePaymentRequest{}
+	if !decodeAndValidate(w, r, &request) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if request.PaymentMethodId != 0 {
+		methodType, method, ok := s.paymentMethod(request.PaymentMethodId)
+		if !ok || method.Status != "ENABLED" {
+			writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypePaymentMethodNotAvailable, "payment method is not available")
+			return
+		}
+		if !authorizationCodeMatches(methodType, method, request.AuthorizationCode) {
+			writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, "authorizationCode does not match the payment method")
+			return
+		}
+	}
+	if request.PaymentMethodToken != "" {
+		if _, ok := s.savedInstrument(request.Buyer.ExternalId, request.PaymentMethodToken); !ok {
+			writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, "paymentMethodToken is not saved for the buyer")
+			return
+		}
+	}
+	idempotencyKey := "payments:" + r.Header.Get("Idempotency-Key")
+	payment, ok := s.payments[s.idempotency[idempotencyKey]]
+	if !ok {
+		payment = &Payment{
+			Id:             s.nextId("PNW"),
+			IdempotencyKey: r.Header.Get("Idempotency-Key"),
+			Request:        request,
+			Status:         paynow_sdk.PaymentStatusNew,
+		}
+		if request.AuthorizationCode != "" {
+			// White-label BLIK or a wallet token, Paynow authorizes the payment asynchronously. Move it on with UpdatePaymentStatus.
+			payment.Status = paynow_sdk.PaymentStatusPending
+		}
+		s.payments[payment.Id] = payment
+		s.idempotency[idempotencyKey] = payment.Id
+	}
+	response := paynow_sdk.CreatePaymentResponse{
+		PaymentId: payment.Id,
+		Status:    payment.Status,
+	}
+	if payment.Request.AuthorizationCode == "" {
+		response.RedirectUrl = s.URL + "/pay/" + payment.Id
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// savedInstrument must be called with s.mu held, it returns the index of the instrument.
+func (s *Server) savedInstrument(externalBuyerId, token string) (int, bool) {
+	for i, instrument := range s.savedInstruments[externalBuyerId] {
+		if instrument.Token == token {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// paymentMethod must be called with s.mu held. It returns the method and the type of its group.
+func (s *Server) paymentMethod(id int64) (string, paynow_sdk.PaymentMethod, bool) {
+	for _, group := range s.paymentMethods {
+		for _, method := range group.PaymentMethods {
+			if method.Id == id {
+				return group.Type, method, true
+			}
+		}
+	}
+	return "", paynow_sdk.PaymentMethod{}, false
+}
+
+// authorizationCodeMatches checks that a BLIK method gets a 6-digit code, a wallet gets a base64 token
+// and methods without AuthorizationTypeCode get no code at all.
+func authorizationCodeMatches(methodType string, method paynow_sdk.PaymentMethod, code string) bool {
+	if !method.RequiresAuthorizationCode() {
+		return code == ""
+	}
+	isBlikCode := len(code) == 6 && strings.Trim(code, "0123456789") == ""
+	switch methodType {
+	case paynow_sdk.PaymentMethodTypeBlik:
+		return isBlikCode
+	case paynow_sdk.PaymentMethodTypeGooglePay, paynow_sdk.PaymentMethodTypeApplePay:
+		token, err := base64.StdEncoding.DecodeString(code)
+		return err == nil && json.Valid(token)
+	default:
+		return code != ""
+	}
+}
+
+func (s *Server) getPaymentStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payment, ok := s.payments[r.PathValue("paymentId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, paynow_sdk.ErrorTypeNotFound, "payment not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, paynow_sdk.GetPaymentStatusResponse{PaymentId: payment.Id, Status: payment.Status})
+}
+
+func (s *Server) getPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	query := paynow_sdk.GetPaymentMethodsQuery{
+		Currency:        r.URL.Query().Get("currency"),
+		ApplePayEnabled: r.URL.Query().Get("applePayEnabled") == "true",
+		ExternalBuyerId: r.URL.Query().Get("externalBuyerId"),
+	}
+	if err := query.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instruments := s.savedInstruments[query.ExternalBuyerId]
+	groups := make([]paynow_sdk.GetPaymentMethodsResponse, 0, len(s.paymentMethods))
+	for _, group := range s.paymentMethods {
+		if group.Type == paynow_sdk.PaymentMethodTypeApplePay && !query.ApplePayEnabled {
+			continue
+		}
+		if group.Type == paynow_sdk.PaymentMethodTypeCard && len(instruments) != 0 {
+			methods := make([]paynow_sdk.PaymentMethod, len(group.PaymentMethods))
+			for i, method := range group.PaymentMethods {
+				method.SavedInstruments = instruments
+				methods[i] = method
+			}
+			group.PaymentMethods = methods
+		}
+		groups = append(groups, group)
+	}
+	writeJSON(w, http.StatusOK, groups)
+}
+
+// getGDPRNotices returns the notices in the requested locale or, failing that, in the same language.
+// All notices are returned when the locale is empty or nothing matches.
+func (s *Server) getGDPRNotices(w http.ResponseWriter, r *http.Request) {
+	locale := r.URL.Query().Get("locale")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if locale == "" {
+		writeJSON(w, http.StatusOK, s.gdprNotices)
+		return
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	var exact, sameLanguage []paynow_sdk.GetGDPRClausesResponseItem
+	for _, notice := range s.gdprNotices {
+		noticeLanguage, _, _ := strings.Cut(notice.Locale, "-")
+		switch {
+		case strings.EqualFold(notice.Locale, locale):
+			exact = append(exact, notice)
+		case strings.EqualFold(noticeLanguage, language):
+			sameLanguage = append(sameLanguage, notice)
+		}
+	}
+	switch {
+	case len(exact) != 0:
+		writeJSON(w, http.StatusOK, exact)
+	case len(sameLanguage) != 0:
+		writeJSON(w, http.StatusOK, sameLanguage)
+	default:
+		writeJSON(w, http.StatusOK, s.gdprNotices)
+	}
+}
+
+func (s *Server) createRefund(w http.ResponseWriter, r *http.Request) {
+	request := paynow_sdk.CreateRefundRequest{}
+	if !decodeAndValidate(w, r, &request) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payment, ok := s.payments[r.PathValue("paymentId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, paynow_sdk.ErrorTypeNotFound, "payment not found")
+		return
+	}
+	idempotencyKey := "refunds:" + r.Header.Get("Idempotency-Key")
+	if refund, ok := s.refunds[s.idempotency[idempotencyKey]]; ok {
+		writeJSON(w, http.StatusCreated, paynow_sdk.CreateRefundResponse{RefundId: refund.Id, Status: refund.Status})
+		return
+	}
+	if payment.Status != paynow_sdk.PaymentStatusConfirmed && payment.Status != paynow_sdk.PaymentStatusCompleted {
+		writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, "payment is not confirmed")
+		return
+	}
+	var refunded int64
+	for _, refundId := range payment.Refunds {
+		if refund := s.refunds[refundId]; refund.Status != paynow_sdk.RefundStatusFailed && refund.Status != paynow_sdk.RefundStatusCancelled {
+			refunded += refund.Request.Amount
+		}
+	}
+	if refunded+request.Amount > payment.Request.Amount {
+		writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeInsufficientBalanceFunds, "refund amount exceeds the payment amount")
+		return
+	}
+	refund := &Refund{
+		Id:             s.nextId("RFD"),
+		PaymentId:      payment.Id,
+		IdempotencyKey: r.Header.Get("Idempotency-Key"),
+		Request:        request,
+		Status:         paynow_sdk.RefundStatusNew,
+	}
+	s.refunds[refund.Id] = refund
+	s.idempotency[idempotencyKey] = refund.Id
+	payment.Refunds = append(payment.Refunds, refund.Id)
+	writeJSON(w, http.StatusCreated, paynow_sdk.CreateRefundResponse{RefundId: refund.Id, Status: refund.Status})
+}
+
+func (s *Server) getRefundStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refund, ok := s.refunds[r.PathValue("refundId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, paynow_sdk.ErrorTypeNotFound, "refund not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, paynow_sdk.GetRefundStatusResponse{RefundId: refund.Id, Status: refund.Status})
+}
+
+func (s *Server) cancelRefund(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refund, ok := s.refunds[r.PathValue("refundId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, paynow_sdk.ErrorTypeNotFound, "refund not found")
+		return
+	}
+	if refund.Status != paynow_sdk.RefundStatusNew && refund.Status != paynow_sdk.RefundStatusCancelled {
+		writeError(w, http.StatusConflict, paynow_sdk.ErrorTypeValidationError, "refund can no longer be cancelled")
+		return
+	}
+	refund.Status = paynow_sdk.RefundStatusCancelled
+	writeJSON(w, http.StatusOK, paynow_sdk.GetRefundStatusResponse{RefundId: refund.Id, Status: refund.Status})
+}
+
+func (s *Server) patchShopURLs(w http.ResponseWriter, r *http.Request) {
+	request := paynow_sdk.PatchShopURLsRequest{}
+	if !decodeAndValidate(w, r, &request) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if request.NotificationUrl != "" {
+		s.notificationUrl = request.NotificationUrl
+	}
+	if request.ContinueUrl != "" {
+		s.continueUrl = request.ContinueUrl
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeSavedInstrument(w http.ResponseWriter, r *http.Request) {
+	query := paynow_sdk.RemoveSavedInstrumentQuery{
+		ExternalBuyerId: r.URL.Query().Get("externalBuyerId"),
+		Token:           r.URL.Query().Get("token"),
+	}
+	if err := query.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.savedInstrument(query.ExternalBuyerId, query.Token)
+	if !ok {
+		writeError(w, http.StatusNotFound, paynow_sdk.ErrorTypeNotFound, "saved instrument not found")
+		return
+	}
+	instruments := s.savedInstruments[query.ExternalBuyerId]
+	s.savedInstruments[query.ExternalBuyerId] = append(instruments[:i:i], instruments[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, request paynow_sdk.RequestType) bool {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, "malformed request body")
+		return false
+	}
+	if err := request.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, paynow_sdk.ErrorTypeValidationError, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, errorType, message string) {
+	writeJSON(w, statusCode, paynow_sdk.ErrorResponse{
+		StatusCode: statusCode,
+		Errors:     []paynow_sdk.Error{{ErrorType: errorType, Message: message}},
+	})
+}
@@ -0,0 +1,179 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/time/rate"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket: Rate requests per second on average, with bursts of up to Burst requests.
+type RateLimit struct {
+	Rate  float64 // Requests per second, values below or equal to 0 disable the limit
+	Burst int     // Bucket size, values below 1 are treated as 1
+}
+
+// RateLimitPolicy limits requests sent by the client. A request waits for a token from the global bucket
+// and from the bucket of its operation, if one is configured.
+// When Paynow answers 429 Too Many Requests, the rate of both buckets is lowered to Rate*BackoffFactor
+// for BackoffDuration, or for as long as the Retry-After header asks if that is longer.
+type RateLimitPolicy struct {
+	Global          RateLimit            // Limit shared by all requests
+	PerOperation    map[string]RateLimit // Limits keyed by Operation* constants, e.g. OperationGetPaymentStatus
+	BackoffFactor   float64              // Rate multiplier applied after a 429, 0.5 when not in (0, 1)
+	BackoffDuration time.Duration        // How long the lowered rate is kept, 30 seconds when 0
+}
+
+// errRateLimitWait wraps errors of requests that were not sent because no token became available in time.
+var errRateLimitWait = errors.New("failed to wait for rate limiter")
+
+const (
+	defaultRateLimitBackoffFactor   = 0.5
+	defaultRateLimitBackoffDuration = 30 * time.Second
+)
+
+// WithRateLimit enables the client-side rate limiter. Requests block until a token is available
+// or the context is done. Every retry made by WithRetryPolicy takes a token too, and a 429 answered
+// to any attempt lowers the rate; a retry that cannot get a token is not made.
+func WithRateLimit(policy RateLimitPolicy) Option {
+	return func(o *clientOptions) {
+		o.rateLimit = &policy
+	}
+}
+
+// adaptiveLimiter is a token bucket whose rate can be lowered temporarily.
+type adaptiveLimiter struct {
+	limiter   *rate.Limiter
+	base      rate.Limit
+	mu        sync.Mutex
+	restoreAt time.Time
+}
+
+func newAdaptiveLimiter(limit RateLimit) *adaptiveLimiter {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	burst := max(limit.Burst, 1)
+	return &adaptiveLimiter{limiter: rate.NewLimiter(rate.Limit(limit.Rate), burst), base: rate.Limit(limit.Rate)}
+}
+
+func (l *adaptiveLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.restoreAt.IsZero() && !time.Now().Before(l.restoreAt) {
+		l.limiter.SetLimit(l.base)
+		l.restoreAt = time.Time{}
+	}
+	l.mu.Unlock()
+	return l.limiter.Wait(ctx)
+}
+
+// throttle lowers the rate to base*factor until the given duration passes. Repeated calls extend the period
+// but do not lower the rate further.
+func (l *adaptiveLimiter) throttle(factor float64, duration time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limiter.SetLimit(l.base * rate.Limit(factor))
+	if restoreAt := time.Now().Add(duration); restoreAt.After(l.restoreAt) {
+		l.restoreAt = restoreAt
+	}
+}
+
+type rateLimiter struct {
+	global          *adaptiveLimiter
+	perOperation    map[string]*adaptiveLimiter
+	backoffFactor   float64
+	backoffDuration time.Duration
+}
+
+func newRateLimiter(policy RateLimitPolicy) *rateLimiter {
+	limiter := &rateLimiter{
+		global:          newAdaptiveLimiter(policy.Global),
+		perOperation:    make(map[string]*adaptiveLimiter, len(policy.PerOperation)),
+		backoffFactor:   policy.BackoffFactor,
+		backoffDuration: policy.BackoffDuration,
+	}
+	if limiter.backoffFactor <= 0 || limiter.backoffFactor >= 1 {
+		limiter.backoffFactor = defaultRateLimitBackoffFactor
+	}
+	if limiter.backoffDuration <= 0 {
+		limiter.backoffDuration = defaultRateLimitBackoffDuration
+	}
+	for operation, limit := range policy.PerOperation {
+		if operationLimiter := newAdaptiveLimiter(limit); operationLimiter != nil {
+			limiter.perOperation[operation] = operationLimiter
+		}
+	}
+	return limiter
+}
+
+// limiters returns the buckets a request of the given operation takes tokens from.
+func (r *rateLimiter) limiters(operation string) []*adaptiveLimiter {
+	limiters := make([]*adaptiveLimiter, 0, 2)
+	if r.global != nil {
+		limiters = append(limiters, r.global)
+	}
+	if operationLimiter, ok := r.perOperation[operation]; ok {
+		limiters = append(limiters, operationLimiter)
+	}
+	return limiters
+}
+
+func (r *rateLimiter) middleware(next Handler) Handler {
+	return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+		limiters := r.limiters(req.Operation)
+		if err := r.wait(ctx, limiters); err != nil {
+			return nil, err
+		}
+		resp, err := next(ctx, req)
+		if err == nil {
+			r.observe(limiters, resp.StatusCode, resp.Header)
+		}
+		return resp, err
+	}
+}
+
+// beforeRetry is called by RetryPolicy with the response of a failed attempt, before it is retried. It lowers
+// the rate when the attempt got a 429 and waits for the tokens of the next attempt.
+func (r *rateLimiter) beforeRetry(ctx context.Context, operation string, statusCode int, header http.Header) error {
+	limiters := r.limiters(operation)
+	r.observe(limiters, statusCode, header)
+	return r.wait(ctx, limiters)
+}
+
+func (r *rateLimiter) wait(ctx context.Context, limiters []*adaptiveLimiter) error {
+	for _, limiter := range limiters {
+		if err := limiter.wait(ctx); err != nil {
+			return fmt.Errorf("%w: %w", errRateLimitWait, err)
+		}
+	}
+	return nil
+}
+
+func (r *rateLimiter) observe(limiters []*adaptiveLimiter, statusCode int, header http.Header) {
+	if statusCode != http.StatusTooManyRequests {
+		return
+	}
+	duration := max(r.backoffDuration, retryAfter(header))
+	for _, limiter := range limiters {
+		limiter.throttle(r.backoffFactor, duration)
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date, 0 when missing or invalid.
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/time/rate"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitThrottlesOnRetried429(t *testing.T) {
+	var attempts []RetryAttempt
+	url, calls := newRetryTestServer(t,
+		respondWithStatus(http.StatusTooManyRequests, `{"statusCode":429,"errors":[]}`),
+		respondWithStatus(http.StatusOK, `{"paymentId":"P1","status":"NEW"}`),
+	)
+	client, err := NewPayNowApiClient("key", "secret", url,
+		WithRetryPolicy(testRetryPolicy(2, &attempts)),
+		WithRateLimit(RateLimitPolicy{
+			Global:          RateLimit{Rate: 1000, Burst: 2},
+			PerOperation:    map[string]RateLimit{OperationGetPaymentStatus: {Rate: 10, Burst: 2}},
+			BackoffFactor:   0.5,
+			BackoffDuration: time.Minute,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewPayNowApiClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetPaymentStatus(context.Background(), "P1"); err != nil {
+		t.Fatalf("GetPaymentStatus() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server got %d attempts, want 2", got)
+	}
+	global, perOperation := client.rateLimiter.global.limiter, client.rateLimiter.perOperation[OperationGetPaymentStatus].limiter
+	if got := global.Limit(); got != rate.Limit(500) {
+		t.Errorf("global rate = %v, want 500 after the retried 429", got)
+	}
+	if got := perOperation.Limit(); got != rate.Limit(5) {
+		t.Errorf("%s rate = %v, want 5 after the retried 429", OperationGetPaymentStatus, got)
+	}
+	// Both attempts took a token from the burst of 2.
+	if got := perOperation.Tokens(); got >= 1 {
+		t.Errorf("%s bucket has %.2f tokens left, want less than 1 after two attempts", OperationGetPaymentStatus, got)
+	}
+}
+
+func TestRateLimitSkipsRetryWithoutToken(t *testing.T) {
+	var attempts []RetryAttempt
+	url, calls := newRetryTestServer(t,
+		respondWithStatus(http.StatusServiceUnavailable, `{"statusCode":503,"errors":[]}`),
+		respondWithStatus(http.StatusOK, `{"paymentId":"P1","status":"NEW"}`),
+	)
+	client, err := NewPayNowApiClient("key", "secret", url,
+		WithRetryPolicy(testRetryPolicy(2, &attempts)),
+		WithRateLimit(RateLimitPolicy{Global: RateLimit{Rate: 0.01, Burst: 1}}),
+	)
+	if err != nil {
+		t.Fatalf("NewPayNowApiClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// The next token comes in 100s, after the deadline, so the retry is not made and the 503 is returned.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = client.GetPaymentStatus(ctx, "P1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("GetPaymentStatus() error = %v, want the 503 API error", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server got %d attempts, want 1", got)
+	}
+	if len(attempts) != 0 {
+		t.Errorf("OnRetry called with %+v, want no retries", attempts)
+	}
+}
@@ -5,21 +5,59 @@ import (
 	"regexp"
 )
 
+// RequestType is implemented by every request payload, Validate returns ValidationErrors listing all invalid fields.
 type RequestType interface {
 	Validate() error
 }
 
+var (
+	supportedCurrencies  = []string{"PLN", "EUR", "USD", "GBP", "CZK"}
+	refundReasons        = []string{"RMA", "REFUND_BEFORE_14", "REFUND_AFTER_14", "OTHER"}
+	polishZipcodePattern = regexp.MustCompile(`^\d{2}-\d{3}$`)
+	countryCodePattern   = regexp.MustCompile(`^[A-Z]{2}$`)
+	phonePrefixPattern   = regexp.MustCompile(`^\+\d{1,4}$`)
+	emailPattern         = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	blikCodePattern      = regexp.MustCompile(`^\d{6}$`)
+)
+
 type GetPaymentMethodsQuery struct {
 	Amount          int64  `json:"amount,omitempty"`
 	Currency        string `json:"currency,omitempty"` // ISO 4217 currency code
 	ApplePayEnabled bool   `json:"applePayEnabled,omitempty"`
+	ExternalBuyerId string `json:"externalBuyerId,omitempty"` // Buyer's ID in the merchant's system, returns the cards saved by this buyer
 }
 
 func (g *GetPaymentMethodsQuery) Validate() error {
-	if g.Currency != "PLN" && g.Currency != "EUR" && g.Currency != "USD" && g.Currency != "GBP" && g.Currency != "CZK" {
-		return fmt.Errorf("invalid currency: %s, must be one of [PLN, EUR, USD, GBP, CZK]", g.Currency)
+	var errs ValidationErrors
+	if g.Currency != "" {
+		errs.oneOf("currency", g.Currency, supportedCurrencies)
 	}
-	return nil
+	errs.maxLength("externalBuyerId", g.ExternalBuyerId, 100)
+	return errs.errOrNil()
+}
+
+type GetGDPRClausesQuery struct {
+	Locale string `json:"locale,omitempty"` // Language tag compliant with BCP47/RFC5646, e.g. "pl-PL"
+}
+
+func (g *GetGDPRClausesQuery) Validate() error {
+	var errs ValidationErrors
+	errs.maxLength("locale", g.Locale, 35)
+	return errs.errOrNil()
+}
+
+type RemoveSavedInstrumentQuery struct {
+	ExternalBuyerId string `json:"externalBuyerId"` // Buyer's ID in the merchant's system, the same as BuyerInfo.ExternalId
+	Token           string `json:"token"`           // SavedInstrument.Token of the card to remove
+}
+
+func (r *RemoveSavedInstrumentQuery) Validate() error {
+	var errs ValidationErrors
+	if errs.required("externalBuyerId", r.ExternalBuyerId) {
+		errs.maxLength("externalBuyerId", r.ExternalBuyerId, 100)
+	}
+	errs.required("token", r.Token)
+	return errs.errOrNil()
 }
 
 type Phone struct {
@@ -28,58 +66,41 @@ type Phone struct {
 }
 
 func (p *Phone) Validate() error {
-	if p.Prefix == "" {
-		return fmt.Errorf("phone prefix cannot be empty")
-	}
-	if p.Number <= 0 {
-		return fmt.Errorf("phone number must be a positive integer")
-	}
-	if p.Number > 9999999999 {
-		return fmt.Errorf("phone number is too long, must be less than or equal to 10 digits")
+	var errs ValidationErrors
+	if errs.required("prefix", p.Prefix) {
+		errs.matches("prefix", p.Prefix, phonePrefixPattern, "+48")
 	}
-	return nil
+	errs.inRange("number", p.Number, 1, 9999999999)
+	return errs.errOrNil()
 }
 
 type AddressType struct {
 	Street          string `json:"street,omitempty"`          // Street address, e.g., "123 Main St".
 	HouseNumber     string `json:"houseNumber,omitempty"`     // House number, e.g., "12A".
 	ApartmentNumber string `json:"apartmentNumber,omitempty"` // Apartment number, e.g., "3B".
-	Zipcode         string `json:"zipcode,omitempty"`         // Postal code, e.g., "00-123".
+	Zipcode         string `json:"zipcode,omitempty"`         // Postal code, e.g., "00-123", the XX-XXX format is enforced only for Polish addresses.
 	City            string `json:"city,omitempty"`            // City name, e.g., "Warsaw".
 	County          string `json:"county,omitempty"`          // County name, e.g., "Warsaw County".
-	Country         string `json:"country,omitempty"`         // Country code, e.g., "PL" for Poland.
+	Country         string `json:"country,omitempty"`         // ISO 3166-1 alpha-2 country code, e.g., "PL" for Poland.
 }
 
 func (a *AddressType) Validate() error {
-	if a.Street != "" && len(a.Street) >= 100 {
-		return fmt.Errorf("street address is too long, must be less than or equal to 100 characters")
-	}
-	if a.HouseNumber != "" && len(a.HouseNumber) >= 16 {
-		return fmt.Errorf("house number is too long, must be less than or equal to 16 characters")
-	}
-	if a.ApartmentNumber != "" && len(a.ApartmentNumber) >= 16 {
-		return fmt.Errorf("apartment number is too long, must be less than or equal to 16 characters")
-	}
-	if a.Zipcode != "" && len(a.Zipcode) >= 16 {
-		return fmt.Errorf("zipcode is too long, must be less than or equal to 16 characters")
-	}
-	matched, err := regexp.MatchString(`^\d{2}-\d{3}$`, a.Zipcode)
-	if err != nil {
-		return fmt.Errorf("error validating zipcode: %v", err)
-	}
-	if a.Zipcode != "" && !matched {
-		return fmt.Errorf("zipcode must be in the format 'XX-XXX', e.g., '00-123'")
-	}
-	if a.City != "" && len(a.City) >= 100 && len(a.City) <= 2 {
-		return fmt.Errorf("city name is too long, must be between 2 and 100 characters")
-	}
-	if a.County != "" && len(a.County) >= 100 {
-		return fmt.Errorf("county name is too long, must be less than or equal to 100 characters")
-	}
-	if a.Country != "" && len(a.Country) != 2 {
-		return fmt.Errorf("country code must be exactly 2 characters, e.g., 'PL' for Poland")
-	}
-	return nil
+	var errs ValidationErrors
+	errs.maxLength("street", a.Street, 100)
+	errs.maxLength("houseNumber", a.HouseNumber, 16)
+	errs.maxLength("apartmentNumber", a.ApartmentNumber, 16)
+	errs.maxLength("zipcode", a.Zipcode, 16)
+	if a.Zipcode != "" && a.Country == "PL" {
+		errs.matches("zipcode", a.Zipcode, polishZipcodePattern, "XX-XXX")
+	}
+	if a.City != "" {
+		errs.lengthBetween("city", a.City, 2, 100)
+	}
+	errs.maxLength("county", a.County, 100)
+	if a.Country != "" {
+		errs.matches("country", a.Country, countryCodePattern, "PL")
+	}
+	return errs.errOrNil()
 }
 
 type Address struct {
@@ -88,17 +109,14 @@ type Address struct {
 }
 
 func (a *Address) Validate() error {
+	var errs ValidationErrors
 	if a.Billing != nil {
-		if err := a.Billing.Validate(); err != nil {
-			return fmt.Errorf("billing address validation failed: %v", err)
-		}
+		errs.addNested("billing", a.Billing.Validate())
 	}
 	if a.Shipping != nil {
-		if err := a.Shipping.Validate(); err != nil {
-			return fmt.Errorf("shipping address validation failed: %v", err)
-		}
+		errs.addNested("shipping", a.Shipping.Validate())
 	}
-	return nil
+	return errs.errOrNil()
 }
 
 type BuyerInfo struct {
@@ -113,35 +131,22 @@ type BuyerInfo struct {
 }
 
 func (b *BuyerInfo) Validate() error {
-	if b.Email == "" {
-		return fmt.Errorf("email cannot be empty")
-	}
-	if len(b.Email) > 50 {
-		return fmt.Errorf("email is too long, must be less than or equal to 254 characters")
-	}
-	if b.FirstName != "" && len(b.FirstName) > 50 {
-		return fmt.Errorf("first name is too long, must be less than or equal to 100 characters")
-	}
-	if b.LastName != "" && len(b.LastName) > 50 {
-		return fmt.Errorf("last name is too long, must be less than or equal to 100 characters")
-	}
-	if b.Phone == nil {
-		if err := b.Phone.Validate(); err != nil {
-			return fmt.Errorf("phone validation failed: %v", err)
-		}
-	}
-	if b.Address == nil {
-		if err := b.Address.Validate(); err != nil {
-			return fmt.Errorf("address validation failed: %v", err)
-		}
-	}
-	if b.Locale != "" && len(b.Locale) > 35 {
-		return fmt.Errorf("locale is too long, must be less than or equal to 10 characters")
-	}
-	if b.ExternalId != "" && len(b.ExternalId) > 100 {
-		return fmt.Errorf("externalId is too long, must be less than or equal to 50 characters")
-	}
-	return nil
+	var errs ValidationErrors
+	if errs.required("email", b.Email) {
+		errs.maxLength("email", b.Email, 254)
+		errs.matches("email", b.Email, emailPattern, "name@example.com")
+	}
+	errs.maxLength("firstName", b.FirstName, 50)
+	errs.maxLength("lastName", b.LastName, 50)
+	if b.Phone != nil {
+		errs.addNested("phone", b.Phone.Validate())
+	}
+	if b.Address != nil {
+		errs.addNested("address", b.Address.Validate())
+	}
+	errs.maxLength("locale", b.Locale, 35)
+	errs.maxLength("externalId", b.ExternalId, 100)
+	return errs.errOrNil()
 }
 
 type OrderItem struct {
@@ -153,25 +158,17 @@ type OrderItem struct {
 }
 
 func (o *OrderItem) Validate() error {
-	if o.Name == "" {
-		return fmt.Errorf("item name cannot be empty")
-	}
-	if len(o.Name) > 120 {
-		return fmt.Errorf("item name is too long, must be less than or equal to 100 characters")
-	}
-	if o.Producer != "" && len(o.Producer) > 120 {
-		return fmt.Errorf("producer name is too long, must be less than or equal to 100 characters")
-	}
-	if o.Category == "" || len(o.Category) > 1000 {
-		return fmt.Errorf("category name is too long, must be less than or equal to 100 characters")
+	var errs ValidationErrors
+	if errs.required("name", o.Name) {
+		errs.maxLength("name", o.Name, 120)
 	}
-	if o.Quantity > 9999999999 || o.Quantity <= 0 {
-		return fmt.Errorf("quantity must be a positive integer and less than or equal to 10 digits")
+	errs.maxLength("producer", o.Producer, 120)
+	if errs.required("category", o.Category) {
+		errs.maxLength("category", o.Category, 1000)
 	}
-	if o.Price <= 0 || o.Price > 9999999999 {
-		return fmt.Errorf("price must be a positive integer and less than or equal to 10 digits")
-	}
-	return nil
+	errs.inRange("quantity", o.Quantity, 1, 9999999999)
+	errs.inRange("price", o.Price, 1, 9999999999)
+	return errs.errOrNil()
 }
 
 type CreatePaymentRequest struct {
@@ -184,40 +181,61 @@ type CreatePaymentRequest struct {
 	ContinueUrl   string       `json:"continueUrl,omitempty"`
 	ValidityTime  int64        `json:"validityTime,omitempty"`  // Time in seconds until the payment expires
 	PayoutAccount string       `json:"payoutAccount,omitempty"` // Account to which the payment will be made, e.g., "PL61109010140000071219812874".
+	// Payment method chosen on the merchant's page, see GetPaymentMethods. The buyer skips the method selection on Paynow's page.
+	PaymentMethodId int64 `json:"paymentMethodId,omitempty"`
+	// 6-digit BLIK code typed by the buyer, for a PaymentMethodId with AuthorizationTypeCode. The payment has no redirect
+	// and is confirmed by the buyer in the banking app. For Google Pay and Apple Pay it carries the wallet token, see SetWalletToken.
+	AuthorizationCode string `json:"authorizationCode,omitempty"`
+	// SavedInstrument.Token of a card saved by the buyer. Buyer.ExternalId must be the buyer the card was saved for.
+	PaymentMethodToken string `json:"paymentMethodToken,omitempty"`
 }
 
 func (c *CreatePaymentRequest) Validate() error {
-	if c.Amount <= 0 || c.Amount > 9999999999 {
-		return fmt.Errorf("amount must be a positive integer and less than or equal to 10 digits")
+	var errs ValidationErrors
+	errs.inRange("amount", c.Amount, 1, 9999999999)
+	if c.Currency != "" {
+		errs.oneOf("currency", c.Currency, supportedCurrencies)
 	}
-	if c.Currency != "PLN" && c.Currency != "EUR" && c.Currency != "USD" && c.Currency != "GBP" && c.Currency != "CZK" {
-		return fmt.Errorf("invalid currency: %s, must be one of [PLN, EUR, USD, GBP, CZK]", c.Currency)
+	if errs.required("externalId", c.ExternalId) {
+		errs.maxLength("externalId", c.ExternalId, 100)
 	}
-	if c.ExternalId == "" || len(c.ExternalId) > 100 {
-		return fmt.Errorf("externalId cannot be empty and must be less than or equal to 100 characters")
-	}
-	if c.Description == "" || len(c.Description) > 255 {
-		return fmt.Errorf("description cannot be empty and must be less than or equal to 255 characters")
+	if errs.required("description", c.Description) {
+		errs.maxLength("description", c.Description, 255)
 	}
 	if c.Buyer != nil {
-		if err := c.Buyer.Validate(); err != nil {
-			return fmt.Errorf("buyer validation failed: %v", err)
-		}
+		errs.addNested("buyer", c.Buyer.Validate())
 	}
-	if len(c.OrderItems) != 0 {
-		for _, item := range c.OrderItems {
-			if err := item.Validate(); err != nil {
-				return fmt.Errorf("order item validation failed: %v", err)
-			}
+	for i, item := range c.OrderItems {
+		if item == nil {
+			errs.add(fmt.Sprintf("orderItems[%d]", i), ValidationCodeRequired, nil, "cannot be null")
+			continue
 		}
+		errs.addNested(fmt.Sprintf("orderItems[%d]", i), item.Validate())
 	}
-	if c.ContinueUrl != "" && len(c.ContinueUrl) > 1000 {
-		return fmt.Errorf("continueUrl is too long, must be less than or equal to 1000 characters")
+	errs.maxLength("continueUrl", c.ContinueUrl, 1000)
+	if c.ValidityTime != 0 {
+		errs.inRange("validityTime", c.ValidityTime, 60, 864000)
 	}
-	if c.ValidityTime < 60 || c.ValidityTime > 864000 {
-		return fmt.Errorf("validityTime must be between 60 seconds and 10 days (864000 seconds)")
+	if c.PaymentMethodId != 0 {
+		errs.inRange("paymentMethodId", c.PaymentMethodId, 1, 9999999999)
 	}
-	return nil
+	if c.AuthorizationCode != "" {
+		if c.PaymentMethodId == 0 {
+			errs.add("paymentMethodId", ValidationCodeRequired, nil, "cannot be empty when authorizationCode is set")
+		}
+		if !blikCodePattern.MatchString(c.AuthorizationCode) && !isWalletToken(c.AuthorizationCode) {
+			errs.add("authorizationCode", ValidationCodeInvalidFormat, blikCodePattern.String(), "must be a 6-digit BLIK code or a base64-encoded wallet token")
+		}
+	}
+	if c.PaymentMethodToken != "" {
+		if c.AuthorizationCode != "" {
+			errs.add("paymentMethodToken", ValidationCodeInvalidValue, nil, "cannot be used together with authorizationCode")
+		}
+		if c.Buyer == nil || c.Buyer.ExternalId == "" {
+			errs.add("buyer.externalId", ValidationCodeRequired, nil, "cannot be empty when paymentMethodToken is set")
+		}
+	}
+	return errs.errOrNil()
 }
 
 type CreateRefundRequest struct {
@@ -226,13 +244,12 @@ type CreateRefundRequest struct {
 }
 
 func (c *CreateRefundRequest) Validate() error {
-	if c.Amount <= 0 || c.Amount > 9999999999 {
-		return fmt.Errorf("amount must be a positive integer and less than or equal to 10 digits")
-	}
-	if c.Reason != "RMA" && c.Reason != "REFUND_BEFORE_14" && c.Reason != "REFUND_AFTER_14" && c.Reason != "OTHER" {
-		return fmt.Errorf("invalid reason: %s, must be one of [RMA, REFUND_BEFORE_14, REFUND_AFTER_14, OTHER]", c.Reason)
+	var errs ValidationErrors
+	errs.inRange("amount", c.Amount, 1, 9999999999)
+	if c.Reason != "" {
+		errs.oneOf("reason", c.Reason, refundReasons)
 	}
-	return nil
+	return errs.errOrNil()
 }
 
 type PatchShopURLsRequest struct {
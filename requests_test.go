@@ -0,0 +1,163 @@
+package paynow_sdk
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// polishName is 50 characters long but takes 100 bytes.
+var polishName = strings.Repeat("Żółć", 12) + "ąę"
+
+type validationCase struct {
+	name    string
+	request RequestType
+	want    []string // "field:code" of every expected FieldError, in order; empty when the request is valid
+}
+
+func runValidationCases(t *testing.T, cases []validationCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			got := make([]string, 0, len(errs))
+			for _, fieldErr := range errs {
+				got = append(got, fieldErr.Field+":"+fieldErr.Code)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Validate() errors = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPhoneValidate(t *testing.T) {
+	runValidationCases(t, []validationCase{
+		{name: "valid", request: &Phone{Prefix: "+48", Number: 123456789}},
+		{name: "four digit prefix", request: &Phone{Prefix: "+1268", Number: 4601234}},
+		{name: "ten digit number", request: &Phone{Prefix: "+48", Number: 9999999999}},
+		{name: "missing prefix", request: &Phone{Number: 123456789}, want: []string{"prefix:required"}},
+		{name: "prefix without plus", request: &Phone{Prefix: "48", Number: 123456789}, want: []string{"prefix:invalid_format"}},
+		{name: "prefix too long", request: &Phone{Prefix: "+12345", Number: 123456789}, want: []string{"prefix:invalid_format"}},
+		{name: "prefix with letters", request: &Phone{Prefix: "+4a", Number: 123456789}, want: []string{"prefix:invalid_format"}},
+		{name: "missing number", request: &Phone{Prefix: "+48"}, want: []string{"number:out_of_range"}},
+		{name: "negative number", request: &Phone{Prefix: "+48", Number: -1}, want: []string{"number:out_of_range"}},
+		{name: "eleven digit number", request: &Phone{Prefix: "+48", Number: 10000000000}, want: []string{"number:out_of_range"}},
+		{name: "empty", request: &Phone{}, want: []string{"prefix:required", "number:out_of_range"}},
+	})
+}
+
+func TestAddressTypeValidate(t *testing.T) {
+	runValidationCases(t, []validationCase{
+		{name: "empty", request: &AddressType{}},
+		{name: "valid polish", request: &AddressType{Street: "Marszałkowska", HouseNumber: "12A", ApartmentNumber: "3", Zipcode: "00-123", City: "Warszawa", County: "Warszawa", Country: "PL"}},
+		{name: "polish zipcode without dash", request: &AddressType{Zipcode: "00123", Country: "PL"}, want: []string{"zipcode:invalid_format"}},
+		{name: "polish zipcode with letters", request: &AddressType{Zipcode: "AB-123", Country: "PL"}, want: []string{"zipcode:invalid_format"}},
+		{name: "british zipcode", request: &AddressType{Zipcode: "SW1A 1AA", Country: "GB"}},
+		{name: "german zipcode", request: &AddressType{Zipcode: "10115", Country: "DE"}},
+		{name: "zipcode without country", request: &AddressType{Zipcode: "10115"}},
+		{name: "zipcode too long", request: &AddressType{Zipcode: strings.Repeat("1", 17), Country: "DE"}, want: []string{"zipcode:too_long"}},
+		{name: "lowercase country", request: &AddressType{Zipcode: "00123", Country: "pl"}, want: []string{"country:invalid_format"}},
+		{name: "alpha-3 country", request: &AddressType{Country: "POL"}, want: []string{"country:invalid_format"}},
+		{name: "numeric country", request: &AddressType{Country: "61"}, want: []string{"country:invalid_format"}},
+		{name: "city too short", request: &AddressType{City: "W"}, want: []string{"city:too_short"}},
+		{name: "two letter city with diacritics", request: &AddressType{City: "Łą"}},
+		{name: "city too long", request: &AddressType{City: strings.Repeat("a", 101)}, want: []string{"city:too_long"}},
+		{name: "street of 100 polish characters", request: &AddressType{Street: polishName + polishName}},
+		{name: "street too long", request: &AddressType{Street: polishName + polishName + "ą"}, want: []string{"street:too_long"}},
+		{name: "house number too long", request: &AddressType{HouseNumber: strings.Repeat("1", 17)}, want: []string{"houseNumber:too_long"}},
+		{name: "apartment number too long", request: &AddressType{ApartmentNumber: strings.Repeat("1", 17)}, want: []string{"apartmentNumber:too_long"}},
+		{name: "county too long", request: &AddressType{County: strings.Repeat("ż", 101)}, want: []string{"county:too_long"}},
+		{
+			name:    "every field invalid",
+			request: &AddressType{Street: strings.Repeat("a", 101), HouseNumber: strings.Repeat("1", 17), ApartmentNumber: strings.Repeat("1", 17), Zipcode: "1", City: "W", County: strings.Repeat("a", 101), Country: "pl"},
+			want:    []string{"street:too_long", "houseNumber:too_long", "apartmentNumber:too_long", "city:too_short", "county:too_long", "country:invalid_format"},
+		},
+	})
+}
+
+func TestAddressValidate(t *testing.T) {
+	runValidationCases(t, []validationCase{
+		{name: "empty", request: &Address{}},
+		{name: "valid billing and shipping", request: &Address{Billing: &AddressType{Zipcode: "00-123", Country: "PL"}, Shipping: &AddressType{Zipcode: "10115", Country: "DE"}}},
+		{name: "invalid billing", request: &Address{Billing: &AddressType{Zipcode: "00123", Country: "PL"}}, want: []string{"billing.zipcode:invalid_format"}},
+		{name: "invalid shipping", request: &Address{Shipping: &AddressType{Country: "de"}}, want: []string{"shipping.country:invalid_format"}},
+		{
+			name:    "invalid billing and shipping",
+			request: &Address{Billing: &AddressType{City: "W"}, Shipping: &AddressType{City: "K"}},
+			want:    []string{"billing.city:too_short", "shipping.city:too_short"},
+		},
+	})
+}
+
+func TestBuyerInfoValidate(t *testing.T) {
+	runValidationCases(t, []validationCase{
+		{name: "only email", request: &BuyerInfo{Email: "jan.kowalski@example.com"}},
+		{name: "nil phone and address", request: &BuyerInfo{Email: "jan.kowalski@example.com", Phone: nil, Address: nil}},
+		{name: "missing email", request: &BuyerInfo{FirstName: "Jan"}, want: []string{"email:required"}},
+		{name: "email without at", request: &BuyerInfo{Email: "jan.kowalski.example.com"}, want: []string{"email:invalid_format"}},
+		{name: "email without domain dot", request: &BuyerInfo{Email: "jan@localhost"}, want: []string{"email:invalid_format"}},
+		{name: "email with space", request: &BuyerInfo{Email: "jan kowalski@example.com"}, want: []string{"email:invalid_format"}},
+		{name: "email with two ats", request: &BuyerInfo{Email: "jan@kowalski@example.com"}, want: []string{"email:invalid_format"}},
+		{name: "email too long", request: &BuyerInfo{Email: strings.Repeat("a", 243) + "@example.com"}, want: []string{"email:too_long"}},
+		{name: "first name of 50 polish characters", request: &BuyerInfo{Email: "a@b.pl", FirstName: polishName, LastName: polishName}},
+		{name: "first name too long", request: &BuyerInfo{Email: "a@b.pl", FirstName: polishName + "ź"}, want: []string{"firstName:too_long"}},
+		{name: "last name too long", request: &BuyerInfo{Email: "a@b.pl", LastName: strings.Repeat("a", 51)}, want: []string{"lastName:too_long"}},
+		{name: "locale too long", request: &BuyerInfo{Email: "a@b.pl", Locale: strings.Repeat("a", 36)}, want: []string{"locale:too_long"}},
+		{name: "external ID too long", request: &BuyerInfo{Email: "a@b.pl", ExternalId: strings.Repeat("1", 101)}, want: []string{"externalId:too_long"}},
+		{name: "valid phone", request: &BuyerInfo{Email: "a@b.pl", Phone: &Phone{Prefix: "+48", Number: 123456789}}},
+		{name: "invalid phone prefix", request: &BuyerInfo{Email: "a@b.pl", Phone: &Phone{Prefix: "0048", Number: 123456789}}, want: []string{"phone.prefix:invalid_format"}},
+		{name: "empty phone", request: &BuyerInfo{Email: "a@b.pl", Phone: &Phone{}}, want: []string{"phone.prefix:required", "phone.number:out_of_range"}},
+		{name: "empty address", request: &BuyerInfo{Email: "a@b.pl", Address: &Address{}}},
+		{
+			name:    "invalid billing zipcode",
+			request: &BuyerInfo{Email: "a@b.pl", Address: &Address{Billing: &AddressType{Zipcode: "00123", Country: "PL"}}},
+			want:    []string{"address.billing.zipcode:invalid_format"},
+		},
+		{
+			name:    "invalid shipping country",
+			request: &BuyerInfo{Email: "a@b.pl", Address: &Address{Shipping: &AddressType{Country: "pl"}}},
+			want:    []string{"address.shipping.country:invalid_format"},
+		},
+		{
+			name: "every nested rule",
+			request: &BuyerInfo{
+				Email:   "invalid",
+				Phone:   &Phone{Prefix: "48"},
+				Address: &Address{Billing: &AddressType{City: "W"}, Shipping: &AddressType{Zipcode: "1", Country: "PL"}},
+			},
+			want: []string{"email:invalid_format", "phone.prefix:invalid_format", "phone.number:out_of_range", "address.billing.city:too_short", "address.shipping.zipcode:invalid_format"},
+		},
+	})
+}
+
+func TestCreatePaymentRequestValidateBuyerPaths(t *testing.T) {
+	request := &CreatePaymentRequest{
+		Amount:      1000,
+		ExternalId:  "order-1",
+		Description: "Order 1",
+		Buyer:       &BuyerInfo{Email: "a@b.pl", Address: &Address{Billing: &AddressType{Zipcode: "00123", Country: "PL"}}},
+	}
+	var errs ValidationErrors
+	if !errors.As(request.Validate(), &errs) {
+		t.Fatal("Validate() did not return ValidationErrors")
+	}
+	fieldErrs := errs.Field("buyer.address.billing.zipcode")
+	if len(fieldErrs) != 1 {
+		t.Fatalf("Field(%q) = %v, want one error", "buyer.address.billing.zipcode", errs)
+	}
+	if fieldErrs[0].Code != ValidationCodeInvalidFormat || fieldErrs[0].Limit != polishZipcodePattern.String() {
+		t.Errorf("got %+v, want code %q and limit %q", fieldErrs[0], ValidationCodeInvalidFormat, polishZipcodePattern.String())
+	}
+}
@@ -10,15 +10,57 @@ type ErrorResponse struct {
 	Errors     []Error `json:"errors"` // List of error messages
 }
 
+// AuthorizationType tells how the buyer authorizes a payment made with a PaymentMethod.
+type AuthorizationType string
+
+const (
+	AuthorizationTypeRedirect AuthorizationType = "REDIRECT" // The buyer is redirected to Paynow or the bank
+	AuthorizationTypeCode     AuthorizationType = "CODE"     // The buyer types a code on the merchant's page, e.g. BLIK
+)
+
+// Values of PaymentMethod.Status.
+const (
+	PaymentMethodStatusEnabled  = "ENABLED"
+	PaymentMethodStatusDisabled = "DISABLED"
+)
+
 type PaymentMethod struct {
-	Id                int64  `json:"id"`                // Unique identifier for the payment method
-	Name              string `json:"name"`              // Name of the payment method, e.g., "Visa", "MasterCard"
-	Description       string `json:"description"`       // Description of the payment method, e.g., "Credit card payment"
-	Image             string `json:"image"`             // URL to the image representing the payment method
-	Status            string `json:"status"`            // Status of the payment method, Possible values: [ENABLED, DISABLED]
-	AuthorizationType string `json:"authorizationType"` // Type of authorization, Possible values: [REDIRECT, CODE]
+	Id                int64             `json:"id"`                         // Unique identifier for the payment method
+	Name              string            `json:"name"`                       // Name of the payment method, e.g., "Visa", "MasterCard"
+	Description       string            `json:"description"`                // Description of the payment method, e.g., "Credit card payment"
+	Image             string            `json:"image"`                      // URL to the image representing the payment method
+	Status            string            `json:"status"`                     // Status of the payment method, Possible values: [ENABLED, DISABLED]
+	AuthorizationType AuthorizationType `json:"authorizationType"`          // Type of authorization, see the AuthorizationType constants
+	SavedInstruments  []SavedInstrument `json:"savedInstruments,omitempty"` // Cards saved by the buyer, see GetPaymentMethodsQuery.ExternalBuyerId
+}
+
+type SavedInstrumentStatus string
+
+const (
+	SavedInstrumentStatusActive  SavedInstrumentStatus = "ACTIVE"
+	SavedInstrumentStatusExpired SavedInstrumentStatus = "EXPIRED"
+)
 
+// SavedInstrument is a card saved by a buyer in an earlier payment.
+type SavedInstrument struct {
+	Name           string                `json:"name"`           // Masked card number, e.g. "**** **** **** 1234"
+	ExpirationDate string                `json:"expirationDate"` // Card expiration date, e.g. "12/27"
+	Brand          string                `json:"brand"`          // Card brand, e.g. "VISA", "MASTERCARD"
+	Image          string                `json:"image"`          // URL to the image of the card brand
+	Status         SavedInstrumentStatus `json:"status"`         // Status of the card, see the SavedInstrumentStatus constants
+	Token          string                `json:"token"`          // Pass as CreatePaymentRequest.PaymentMethodToken to pay with this card
 }
+
+// RequiresAuthorizationCode reports whether payments with this method need CreatePaymentRequest.AuthorizationCode.
+func (m PaymentMethod) RequiresAuthorizationCode() bool {
+	return m.AuthorizationType == AuthorizationTypeCode
+}
+
+// Enabled reports whether the method can be used for the payment the methods were listed for.
+func (m PaymentMethod) Enabled() bool {
+	return m.Status == PaymentMethodStatusEnabled
+}
+
 type GetPaymentMethodsResponse struct {
 	Type           string          `json:"type"`           // Possible values: [APPLE_PAY, BLIK, CARD, ECOMMERCE, GOOGLE_PAY, PAYPO, PBL]
 	PaymentMethods []PaymentMethod `json:"paymentMethods"` // List of available payment methods
@@ -31,23 +73,23 @@ type GetGDPRClausesResponseItem struct {
 }
 
 type CreatePaymentResponse struct {
-	RedirectUrl string `json:"redirectUrl"`
-	PaymentId   string `json:"paymentId"`
-	Status      string `json:"status"` // "NEW" "PENDING" "ERROR"
+	RedirectUrl string        `json:"redirectUrl"`
+	PaymentId   string        `json:"paymentId"`
+	Status      PaymentStatus `json:"status"` // "NEW" "PENDING" "ERROR"
 }
 
 type GetPaymentStatusResponse struct {
-	PaymentId string `json:"paymentId"` // Unique identifier for the payment
-	Status    string `json:"status"`    // Status of the payment, Possible values: [NEW, PENDING, ERROR, COMPLETED, CANCELED]
+	PaymentId string        `json:"paymentId"` // Unique identifier for the payment
+	Status    PaymentStatus `json:"status"`    // Status of the payment, see the PaymentStatus constants
 }
 
 type CreateRefundResponse struct {
-	RefundId string `json:"refundId"` // Unique identifier for the refund
-	Status   string `json:"status"`   // Status of the refund, Possible values: [NEW, PENDING, ERROR, COMPLETED, CANCELED]
+	RefundId string       `json:"refundId"` // Unique identifier for the refund
+	Status   RefundStatus `json:"status"`   // Status of the refund, see the RefundStatus constants
 }
 
 type GetRefundStatusResponse struct {
-	RefundId      string `json:"refundId"`                // Unique identifier for the refund
-	Status        string `json:"status"`                  // Status of the refund, Possible values: [NEW, PENDING, SUCCESSFUL, FAILED, CANCELLED]
-	FailureReason string `json:"failureReason,omitempty"` // Reason for failure, if applicable Possible values: [CARD_BALANCE_ERROR, BUYER_ACCOUNT_CLOSED, OTHER]
+	RefundId      string       `json:"refundId"`                // Unique identifier for the refund
+	Status        RefundStatus `json:"status"`                  // Status of the refund, see the RefundStatus constants
+	FailureReason string       `json:"failureReason,omitempty"` // Reason for failure, if applicable Possible values: [CARD_BALANCE_ERROR, BUYER_ACCOUNT_CLOSED, OTHER]
 }
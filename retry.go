@@ -0,0 +1,113 @@
+package paynow_sdk
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"resty.dev/v3"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. A request is retried on network errors,
+// 429 Too Many Requests and 5xx responses (except 501). Every attempt re-sends the same
+// Idempotency-Key, Signature and body, so retrying a POST cannot create a duplicate payment or refund.
+type RetryPolicy struct {
+	MaxAttempts int                // Total number of attempts including the first one, values below 2 disable retries
+	MinWait     time.Duration      // Base wait time of the exponential backoff
+	MaxWait     time.Duration      // Upper bound of a single backoff wait, a Retry-After header takes precedence
+	OnRetry     func(RetryAttempt) // Optional hook called after a failed attempt, before waiting for the next one
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried.
+type RetryAttempt struct {
+	Attempt    int   // Number of the failed attempt, starting from 1
+	StatusCode int   // HTTP status of the failed attempt, 0 when no response was received
+	Err        error // Transport error of the failed attempt, nil when a response was received
+}
+
+// DefaultRetryPolicy returns a policy making up to 3 attempts with a jittered backoff between 200ms and 5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinWait:     200 * time.Millisecond,
+		MaxWait:     5 * time.Second,
+	}
+}
+
+// WithRetryPolicy enables automatic retries of transient failures. Retries are disabled by default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+func (p *RetryPolicy) apply(httpClient *resty.Client) {
+	if p.MaxAttempts < 2 {
+		return
+	}
+	httpClient.
+		SetRetryCount(p.MaxAttempts - 1).
+		SetRetryWaitTime(p.MinWait).
+		SetRetryMaxWaitTime(p.MaxWait).
+		SetAllowNonIdempotentRetry(true).
+		SetRetryDefaultConditions(false)
+}
+
+// attach adds the retry condition and the OnRetry hook to a single request. resty passes the hooks the result
+// of resetting the body readers rather than the error of the attempt, so the condition keeps the error for the hook.
+// With a limiter, every retry of operation takes its tokens and 429 responses lower the rate; the last response is
+// returned when no token becomes available.
+func (p *RetryPolicy) attach(request *resty.Request, operation string, limiter *rateLimiter) {
+	if p.MaxAttempts < 2 {
+		return
+	}
+	var attemptErr error
+	request.AddRetryConditions(func(resp *resty.Response, err error) bool {
+		attemptErr = err
+		if !isRetryable(resp, err) {
+			return false
+		}
+		if limiter == nil {
+			return true
+		}
+		var (
+			statusCode int
+			header     http.Header
+		)
+		if resp.RawResponse != nil {
+			statusCode, header = resp.StatusCode(), resp.Header()
+		}
+		return limiter.beforeRetry(resp.Request.Context(), operation, statusCode, header) == nil
+	})
+	if p.OnRetry != nil {
+		request.AddRetryHooks(func(resp *resty.Response, _ error) {
+			attempt := RetryAttempt{Err: attemptErr}
+			if resp != nil {
+				attempt.Attempt = resp.Request.Attempt
+				if resp.RawResponse != nil {
+					attempt.StatusCode = resp.StatusCode()
+				}
+			}
+			p.OnRetry(attempt)
+		})
+	}
+}
+
+// isRetryable retries requests that failed before receiving a response, except on TLS certificate errors,
+// and responses with 429 or a 5xx status other than 501. Nothing is retried once the caller's context is cancelled
+// or past its deadline; an attempt cut off by WithTimeout is retried while that context is still live.
+func isRetryable(resp *resty.Response, err error) bool {
+	if errors.Is(err, context.Canceled) || (resp != nil && resp.Request != nil && resp.Request.Context().Err() != nil) {
+		return false
+	}
+	if resp != nil && resp.RawResponse != nil {
+		status := resp.StatusCode()
+		return status == http.StatusTooManyRequests || (status >= 500 && status != http.StatusNotImplemented)
+	}
+	if err == nil {
+		return false
+	}
+	var certErr *tls.CertificateVerificationError
+	return !errors.As(err, &certErr)
+}
@@ -0,0 +1,247 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRetryTestServer starts a server answering each attempt with the given handlers, in order.
+func newRetryTestServer(t *testing.T, handlers ...http.HandlerFunc) (string, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1)) - 1
+		if call >= len(handlers) {
+			t.Errorf("unexpected attempt %d", call+1)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		handlers[call](w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &calls
+}
+
+// newRetryTestClient returns a client retrying each request up to len(handlers) times against newRetryTestServer.
+func newRetryTestClient(t *testing.T, attempts *[]RetryAttempt, handlers ...http.HandlerFunc) (*PayNowApiClient, *atomic.Int32) {
+	t.Helper()
+	url, calls := newRetryTestServer(t, handlers...)
+	client, err := NewPayNowApiClient("key", "secret", url, WithRetryPolicy(testRetryPolicy(len(handlers), attempts)))
+	if err != nil {
+		t.Fatalf("NewPayNowApiClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, calls
+}
+
+func testRetryPolicy(maxAttempts int, attempts *[]RetryAttempt) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: maxAttempts,
+		MinWait:     time.Millisecond,
+		MaxWait:     5 * time.Millisecond,
+		OnRetry: func(attempt RetryAttempt) {
+			*attempts = append(*attempts, attempt)
+		},
+	}
+}
+
+func dropConnection(w http.ResponseWriter, _ *http.Request) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		panic(err)
+	}
+	conn.Close()
+}
+
+func respondWithStatus(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func TestRetryPolicyOnRetryReportsAttemptErrors(t *testing.T) {
+	var attempts []RetryAttempt
+	client, calls := newRetryTestClient(t, &attempts,
+		dropConnection,
+		respondWithStatus(http.StatusServiceUnavailable, `{"statusCode":503,"errors":[]}`),
+		respondWithStatus(http.StatusOK, `{"paymentId":"P1","status":"NEW"}`),
+	)
+
+	status, err := client.GetPaymentStatus(context.Background(), "P1")
+	if err != nil {
+		t.Fatalf("GetPaymentStatus() error = %v", err)
+	}
+	if status.Status != PaymentStatusNew {
+		t.Errorf("status = %s, want %s", status.Status, PaymentStatusNew)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server got %d attempts, want 3", got)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("OnRetry called %d times, want 2: %+v", len(attempts), attempts)
+	}
+	if attempts[0].Attempt != 1 || attempts[0].StatusCode != 0 || attempts[0].Err == nil {
+		t.Errorf("first retry = %+v, want attempt 1 with a transport error and no status", attempts[0])
+	}
+	if attempts[1].Attempt != 2 || attempts[1].StatusCode != http.StatusServiceUnavailable || attempts[1].Err != nil {
+		t.Errorf("second retry = %+v, want attempt 2 with status 503 and no error", attempts[1])
+	}
+}
+
+func TestRetryPolicySkipsNonRetryableStatuses(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotImplemented} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var attempts []RetryAttempt
+			client, calls := newRetryTestClient(t, &attempts,
+				respondWithStatus(status, `{"statusCode":0,"errors":[]}`),
+				respondWithStatus(http.StatusOK, `{"paymentId":"P1","status":"NEW"}`),
+			)
+
+			if _, err := client.GetPaymentStatus(context.Background(), "P1"); err == nil {
+				t.Fatal("GetPaymentStatus() error = nil, want the API error")
+			}
+			if got := calls.Load(); got != 1 {
+				t.Errorf("server got %d attempts, want 1", got)
+			}
+			if len(attempts) != 0 {
+				t.Errorf("OnRetry called with %+v, want no retries", attempts)
+			}
+		})
+	}
+}
+
+// waitForDisconnect blocks until the client gives up on the request.
+func waitForDisconnect(_ http.ResponseWriter, r *http.Request) {
+	select {
+	case <-r.Context().Done():
+	case <-time.After(5 * time.Second):
+	}
+}
+
+func TestRetryPolicyStopsWhenContextEnds(t *testing.T) {
+	t.Run("cancelled while the response is read", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var attempts []RetryAttempt
+		client, calls := newRetryTestClient(t, &attempts,
+			func(w http.ResponseWriter, r *http.Request) {
+				cancel()
+				respondWithStatus(http.StatusServiceUnavailable, `{"statusCode":503,"errors":[]}`)(w, r)
+			},
+			respondWithStatus(http.StatusOK, `{"paymentId":"P1","status":"NEW"}`),
+		)
+		if _, err := client.GetPaymentStatus(ctx, "P1"); err == nil {
+			t.Fatal("GetPaymentStatus() error = nil, want an error")
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("server got %d attempts, want 1", got)
+		}
+		if len(attempts) != 0 {
+			t.Errorf("OnRetry called with %+v, want no retries", attempts)
+		}
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		var attempts []RetryAttempt
+		client, calls := newRetryTestClient(t, &attempts,
+			waitForDisconnect,
+			respondWithStatus(http.StatusOK, `{"paymentId":"P1","status":"NEW"}`),
+		)
+		if _, err := client.GetPaymentStatus(ctx, "P1"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("GetPaymentStatus() error = %v, want context.DeadlineExceeded", err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("server got %d attempts, want 1", got)
+		}
+		if len(attempts) != 0 {
+			t.Errorf("OnRetry called with %+v, want no retries", attempts)
+		}
+	})
+}
+
+func TestRetryPolicyRetriesAttemptTimeout(t *testing.T) {
+	var attempts []RetryAttempt
+	url, calls := newRetryTestServer(t,
+		waitForDisconnect,
+		respondWithStatus(http.StatusOK, `{"paymentId":"P1","status":"NEW"}`),
+	)
+	client, err := NewPayNowApiClient("key", "secret", url, WithTimeout(50*time.Millisecond), WithRetryPolicy(testRetryPolicy(2, &attempts)))
+	if err != nil {
+		t.Fatalf("NewPayNowApiClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetPaymentStatus(context.Background(), "P1"); err != nil {
+		t.Fatalf("GetPaymentStatus() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server got %d attempts, want 2", got)
+	}
+	if len(attempts) != 1 || !errors.Is(attempts[0].Err, context.DeadlineExceeded) {
+		t.Errorf("OnRetry called with %+v, want one retry after the attempt timeout", attempts)
+	}
+}
+
+func TestRetryPolicyRetriedPostKeepsIdempotencyKeyAndSignature(t *testing.T) {
+	var (
+		attempts []RetryAttempt
+		mu       sync.Mutex
+		headers  []http.Header
+		bodies   []string
+	)
+	record := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			headers = append(headers, r.Header.Clone())
+			bodies = append(bodies, string(body))
+			mu.Unlock()
+			next(w, r)
+		}
+	}
+	client, calls := newRetryTestClient(t, &attempts,
+		record(respondWithStatus(http.StatusServiceUnavailable, `{"statusCode":503,"errors":[]}`)),
+		record(dropConnection),
+		record(respondWithStatus(http.StatusCreated, `{"paymentId":"P1","status":"NEW","redirectUrl":"https://paywall.paynow.pl/P1"}`)),
+	)
+
+	payment, err := client.CreatePayment(context.Background(), &CreatePaymentRequest{
+		Amount:      1000,
+		ExternalId:  "order-1",
+		Description: "Order 1",
+		Buyer:       &BuyerInfo{Email: "jan.kowalski@example.com"},
+	}, "order-1-key")
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+	if payment.PaymentId != "P1" {
+		t.Errorf("PaymentId = %s, want P1", payment.PaymentId)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("server got %d attempts, want 3", got)
+	}
+	for i := 1; i < len(headers); i++ {
+		for _, name := range []string{"Idempotency-Key", "Signature"} {
+			if got, want := headers[i].Get(name), headers[0].Get(name); got != want || got == "" {
+				t.Errorf("attempt %d %s = %q, want %q as in the first attempt", i+1, name, got, want)
+			}
+		}
+		if bodies[i] != bodies[0] {
+			t.Errorf("attempt %d body = %s, want %s", i+1, bodies[i], bodies[0])
+		}
+	}
+	if got := headers[0].Get("Idempotency-Key"); got != "order-1-key" {
+		t.Errorf("Idempotency-Key = %q, want order-1-key", got)
+	}
+}
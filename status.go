@@ -0,0 +1,138 @@
+package paynow_sdk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownStatus is returned by ValidateTransition for a status value this SDK does not know about.
+var ErrUnknownStatus = errors.New("paynow: unknown status")
+
+// ErrIllegalTransition is returned by ValidateTransition for a status change that Paynow never performs.
+var ErrIllegalTransition = errors.New("paynow: illegal status transition")
+
+type PaymentStatus string
+
+const (
+	PaymentStatusNew       PaymentStatus = "NEW"
+	PaymentStatusPending   PaymentStatus = "PENDING"
+	PaymentStatusError     PaymentStatus = "ERROR"
+	PaymentStatusConfirmed PaymentStatus = "CONFIRMED"
+	PaymentStatusRejected  PaymentStatus = "REJECTED"
+	PaymentStatusExpired   PaymentStatus = "EXPIRED"
+	PaymentStatusAbandoned PaymentStatus = "ABANDONED"
+	PaymentStatusCompleted PaymentStatus = "COMPLETED"
+	PaymentStatusCanceled  PaymentStatus = "CANCELED"
+)
+
+// paymentTransitions lists the statuses a payment can move to from a given status. Notifications may arrive
+// out of order or be skipped, so NEW also moves directly to every status reachable through PENDING.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusNew:       {PaymentStatusPending, PaymentStatusConfirmed, PaymentStatusCompleted, PaymentStatusRejected, PaymentStatusError, PaymentStatusExpired, PaymentStatusAbandoned, PaymentStatusCanceled},
+	PaymentStatusPending:   {PaymentStatusConfirmed, PaymentStatusCompleted, PaymentStatusRejected, PaymentStatusError, PaymentStatusExpired, PaymentStatusAbandoned, PaymentStatusCanceled},
+	PaymentStatusError:     {PaymentStatusConfirmed, PaymentStatusCompleted, PaymentStatusRejected},
+	PaymentStatusConfirmed: {},
+	PaymentStatusRejected:  {},
+	PaymentStatusExpired:   {},
+	PaymentStatusAbandoned: {},
+	PaymentStatusCompleted: {},
+	PaymentStatusCanceled:  {},
+}
+
+// IsKnown reports whether s is one of the PaymentStatus constants.
+func (s PaymentStatus) IsKnown() bool {
+	_, ok := paymentTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the payment can no longer change its status.
+func (s PaymentStatus) IsTerminal() bool {
+	next, ok := paymentTransitions[s]
+	return ok && len(next) == 0
+}
+
+// CanTransitionTo reports whether Paynow can move a payment from s to next. Repeating the same status is allowed,
+// as notifications may be delivered more than once.
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	if s == next {
+		return s.IsKnown()
+	}
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an error wrapping ErrUnknownStatus when s or next is not one of the PaymentStatus constants,
+// or ErrIllegalTransition when s cannot move to next.
+func (s PaymentStatus) ValidateTransition(next PaymentStatus) error {
+	for _, status := range []PaymentStatus{s, next} {
+		if !status.IsKnown() {
+			return fmt.Errorf("%w: payment status %q", ErrUnknownStatus, status)
+		}
+	}
+	if !s.CanTransitionTo(next) {
+		return fmt.Errorf("%w: payment %s -> %s", ErrIllegalTransition, s, next)
+	}
+	return nil
+}
+
+type RefundStatus string
+
+const (
+	RefundStatusNew        RefundStatus = "NEW"
+	RefundStatusPending    RefundStatus = "PENDING"
+	RefundStatusSuccessful RefundStatus = "SUCCESSFUL"
+	RefundStatusFailed     RefundStatus = "FAILED"
+	RefundStatusCancelled  RefundStatus = "CANCELLED"
+)
+
+// refundTransitions lists the statuses a refund can move to from a given status.
+var refundTransitions = map[RefundStatus][]RefundStatus{
+	RefundStatusNew:        {RefundStatusPending, RefundStatusSuccessful, RefundStatusFailed, RefundStatusCancelled},
+	RefundStatusPending:    {RefundStatusSuccessful, RefundStatusFailed},
+	RefundStatusSuccessful: {},
+	RefundStatusFailed:     {},
+	RefundStatusCancelled:  {},
+}
+
+// IsKnown reports whether s is one of the RefundStatus constants.
+func (s RefundStatus) IsKnown() bool {
+	_, ok := refundTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the refund can no longer change its status.
+func (s RefundStatus) IsTerminal() bool {
+	next, ok := refundTransitions[s]
+	return ok && len(next) == 0
+}
+
+// CanTransitionTo reports whether Paynow can move a refund from s to next. Repeating the same status is allowed.
+func (s RefundStatus) CanTransitionTo(next RefundStatus) bool {
+	if s == next {
+		return s.IsKnown()
+	}
+	for _, allowed := range refundTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an error wrapping ErrUnknownStatus when s or next is not one of the RefundStatus constants,
+// or ErrIllegalTransition when s cannot move to next.
+func (s RefundStatus) ValidateTransition(next RefundStatus) error {
+	for _, status := range []RefundStatus{s, next} {
+		if !status.IsKnown() {
+			return fmt.Errorf("%w: refund status %q", ErrUnknownStatus, status)
+		}
+	}
+	if !s.CanTransitionTo(next) {
+		return fmt.Errorf("%w: refund %s -> %s", ErrIllegalTransition, s, next)
+	}
+	return nil
+}
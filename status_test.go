@@ -0,0 +1,93 @@
+package paynow_sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPaymentStatusIsTerminal(t *testing.T) {
+	cases := map[PaymentStatus]bool{
+		PaymentStatusNew:       false,
+		PaymentStatusPending:   false,
+		PaymentStatusError:     false,
+		PaymentStatusConfirmed: true,
+		PaymentStatusRejected:  true,
+		PaymentStatusExpired:   true,
+		PaymentStatusAbandoned: true,
+		PaymentStatusCompleted: true,
+		PaymentStatusCanceled:  true,
+		"SETTLED":              false,
+	}
+	for status, want := range cases {
+		if got := status.IsTerminal(); got != want {
+			t.Errorf("%s.IsTerminal() = %t, want %t", status, got, want)
+		}
+	}
+}
+
+func TestRefundStatusIsTerminal(t *testing.T) {
+	cases := map[RefundStatus]bool{
+		RefundStatusNew:        false,
+		RefundStatusPending:    false,
+		RefundStatusSuccessful: true,
+		RefundStatusFailed:     true,
+		RefundStatusCancelled:  true,
+		"REVERSED":             false,
+	}
+	for status, want := range cases {
+		if got := status.IsTerminal(); got != want {
+			t.Errorf("%s.IsTerminal() = %t, want %t", status, got, want)
+		}
+	}
+}
+
+func TestPaymentStatusValidateTransition(t *testing.T) {
+	cases := []struct {
+		from, to PaymentStatus
+		want     error
+	}{
+		{from: PaymentStatusNew, to: PaymentStatusPending},
+		{from: PaymentStatusPending, to: PaymentStatusConfirmed},
+		{from: PaymentStatusNew, to: PaymentStatusConfirmed},
+		{from: PaymentStatusNew, to: PaymentStatusRejected},
+		{from: PaymentStatusNew, to: PaymentStatusCompleted},
+		{from: PaymentStatusNew, to: PaymentStatusExpired},
+		{from: PaymentStatusError, to: PaymentStatusConfirmed},
+		{from: PaymentStatusConfirmed, to: PaymentStatusConfirmed},
+		{from: PaymentStatusCompleted, to: PaymentStatusPending, want: ErrIllegalTransition},
+		{from: PaymentStatusConfirmed, to: PaymentStatusRejected, want: ErrIllegalTransition},
+		{from: PaymentStatusPending, to: PaymentStatusNew, want: ErrIllegalTransition},
+		{from: PaymentStatusError, to: PaymentStatusPending, want: ErrIllegalTransition},
+		{from: PaymentStatusNew, to: "SETTLED", want: ErrUnknownStatus},
+		{from: "SETTLED", to: PaymentStatusConfirmed, want: ErrUnknownStatus},
+		{from: "SETTLED", to: "SETTLED", want: ErrUnknownStatus},
+	}
+	for _, tc := range cases {
+		err := tc.from.ValidateTransition(tc.to)
+		if !errors.Is(err, tc.want) || (tc.want == nil && err != nil) {
+			t.Errorf("%s.ValidateTransition(%s) = %v, want %v", tc.from, tc.to, err, tc.want)
+		}
+	}
+}
+
+func TestRefundStatusValidateTransition(t *testing.T) {
+	cases := []struct {
+		from, to RefundStatus
+		want     error
+	}{
+		{from: RefundStatusNew, to: RefundStatusPending},
+		{from: RefundStatusNew, to: RefundStatusSuccessful},
+		{from: RefundStatusPending, to: RefundStatusFailed},
+		{from: RefundStatusNew, to: RefundStatusCancelled},
+		{from: RefundStatusSuccessful, to: RefundStatusSuccessful},
+		{from: RefundStatusPending, to: RefundStatusCancelled, want: ErrIllegalTransition},
+		{from: RefundStatusSuccessful, to: RefundStatusPending, want: ErrIllegalTransition},
+		{from: RefundStatusNew, to: "REVERSED", want: ErrUnknownStatus},
+	}
+	for _, tc := range cases {
+		err := tc.from.ValidateTransition(tc.to)
+		if !errors.Is(err, tc.want) || (tc.want == nil && err != nil) {
+			t.Errorf("%s.ValidateTransition(%s) = %v, want %v", tc.from, tc.to, err, tc.want)
+		}
+	}
+}
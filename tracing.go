@@ -0,0 +1,74 @@
+package paynow_sdk
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/Hkozacz/paynow-gosdk"
+
+// Span attribute keys set by the client and the notification handler.
+const (
+	AttributeOperation      = attribute.Key("paynow.operation")
+	AttributeEndpoint       = attribute.Key("paynow.endpoint")
+	AttributeHTTPMethod     = attribute.Key("http.request.method")
+	AttributeHTTPStatusCode = attribute.Key("http.response.status_code")
+	AttributePaymentId      = attribute.Key("paynow.payment_id")
+	AttributeRefundId       = attribute.Key("paynow.refund_id")
+	AttributeStatus         = attribute.Key("paynow.status")
+	AttributeErrorTypes     = attribute.Key("paynow.error_types")
+)
+
+// WithTracerProvider creates a client span for every endpoint method using the given provider.
+// Tracing is disabled by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *clientOptions) {
+		if provider != nil {
+			o.tracer = provider.Tracer(tracerName)
+		}
+	}
+}
+
+// WithNotificationTracerProvider creates a server span for every notification handled by NotificationHandler.
+// The span carries the paynow.payment_id attribute, the same as client spans, so both sides can be correlated.
+func WithNotificationTracerProvider(provider trace.TracerProvider) NotificationHandlerOption {
+	return func(h *NotificationHandler) {
+		if provider != nil {
+			h.tracer = provider.Tracer(tracerName)
+		}
+	}
+}
+
+func noopTracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer(tracerName)
+}
+
+type clientSpanKey struct{}
+
+func (c *PayNowApiClient) startSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, AttributeOperation.String(operation))
+	ctx, span := c.tracer.Start(withOperation(ctx, operation), "Paynow "+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return context.WithValue(ctx, clientSpanKey{}, span), span
+}
+
+// clientSpanFromContext returns the span started by startSpan for the current endpoint method, nil for direct
+// Send*Request calls, so the HTTP attributes never end up on a span owned by the caller.
+func clientSpanFromContext(ctx context.Context) trace.Span {
+	span, _ := ctx.Value(clientSpanKey{}).(trace.Span)
+	return span
+}
+
+// recordSpanError marks the span as failed and returns err unchanged.
+func recordSpanError(span trace.Span, err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		span.SetAttributes(AttributeErrorTypes.StringSlice(apiErr.ErrorTypes()))
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
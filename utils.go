@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 )
 
 // SignatureBody represents the structure for the signature calculation
@@ -54,9 +55,21 @@ func GenerateV3(apiKey, signatureKey, idempotencyKey, data string, parameters ma
 	return signature, nil
 }
 
+// SignNotification computes the Signature header Paynow sends with a notification body.
+func SignNotification(signatureKey string, data []byte) string {
+	h := hmac.New(sha256.New, []byte(signatureKey))
+	h.Write(data)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// ConfirmNotificationSignature reports whether signature is the Signature header of data. The comparison takes
+// constant time, a signature that is not valid base64 returns an error.
 func ConfirmNotificationSignature(signatureKey string, data []byte, signature string) (bool, error) {
+	received, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode signature: %w", err)
+	}
 	h := hmac.New(sha256.New, []byte(signatureKey))
 	h.Write(data)
-	payloadSignature := base64.StdEncoding.EncodeToString(h.Sum(nil))
-	return payloadSignature == signature, nil
+	return hmac.Equal(h.Sum(nil), received), nil
 }
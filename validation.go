@@ -0,0 +1,129 @@
+package paynow_sdk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Codes reported in FieldError.Code.
+const (
+	ValidationCodeRequired      = "required"
+	ValidationCodeTooLong       = "too_long"
+	ValidationCodeTooShort      = "too_short"
+	ValidationCodeOutOfRange    = "out_of_range"
+	ValidationCodeInvalidFormat = "invalid_format"
+	ValidationCodeInvalidValue  = "invalid_value"
+)
+
+// FieldError describes a single validation failure of a request field.
+type FieldError struct {
+	Field   string // JSON path of the field, e.g. "buyer.address.billing.zipcode"
+	Code    string // Machine-readable reason, one of the ValidationCode* constants
+	Limit   any    // Limit that was broken, e.g. maximum length, [min, max] range or allowed values; nil when not applicable
+	Message string // Human-readable description
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors lists every violation found by a Validate method. Use errors.As to retrieve it.
+type ValidationErrors []*FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, fieldErr := range v {
+		messages = append(messages, fieldErr.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Field returns the errors reported for the given JSON path.
+func (v ValidationErrors) Field(path string) []*FieldError {
+	var fieldErrors []*FieldError
+	for _, fieldErr := range v {
+		if fieldErr.Field == path {
+			fieldErrors = append(fieldErrors, fieldErr)
+		}
+	}
+	return fieldErrors
+}
+
+func (v ValidationErrors) errOrNil() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v *ValidationErrors) add(field, code string, limit any, message string) {
+	*v = append(*v, &FieldError{Field: field, Code: code, Limit: limit, Message: message})
+}
+
+// addNested merges errors returned by a nested Validate call, prefixing their paths with prefix.
+func (v *ValidationErrors) addNested(prefix string, err error) {
+	if err == nil {
+		return
+	}
+	var nested ValidationErrors
+	if !errors.As(err, &nested) {
+		v.add(prefix, ValidationCodeInvalidValue, nil, err.Error())
+		return
+	}
+	for _, fieldErr := range nested {
+		field := prefix
+		if fieldErr.Field != "" {
+			field = prefix + "." + fieldErr.Field
+		}
+		v.add(field, fieldErr.Code, fieldErr.Limit, fieldErr.Message)
+	}
+}
+
+func (v *ValidationErrors) required(field, value string) bool {
+	if value == "" {
+		v.add(field, ValidationCodeRequired, nil, "cannot be empty")
+		return false
+	}
+	return true
+}
+
+// maxLength counts characters rather than bytes, so Polish diacritics count as one character each.
+func (v *ValidationErrors) maxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, ValidationCodeTooLong, max, fmt.Sprintf("must be less than or equal to %d characters", max))
+	}
+}
+
+func (v *ValidationErrors) lengthBetween(field, value string, min, max int) {
+	length := utf8.RuneCountInString(value)
+	if length < min {
+		v.add(field, ValidationCodeTooShort, min, fmt.Sprintf("must be at least %d characters", min))
+	}
+	if length > max {
+		v.add(field, ValidationCodeTooLong, max, fmt.Sprintf("must be less than or equal to %d characters", max))
+	}
+}
+
+func (v *ValidationErrors) inRange(field string, value, min, max int64) {
+	if value < min || value > max {
+		v.add(field, ValidationCodeOutOfRange, [2]int64{min, max}, fmt.Sprintf("must be between %d and %d", min, max))
+	}
+}
+
+func (v *ValidationErrors) oneOf(field, value string, allowed []string) {
+	if slices.Contains(allowed, value) {
+		return
+	}
+	// Limit gets a copy, so callers modifying it cannot change the package-level lists of allowed values.
+	v.add(field, ValidationCodeInvalidValue, slices.Clone(allowed), fmt.Sprintf("invalid value %q, must be one of [%s]", value, strings.Join(allowed, ", ")))
+}
+
+func (v *ValidationErrors) matches(field, value string, pattern *regexp.Regexp, example string) {
+	if !pattern.MatchString(value) {
+		v.add(field, ValidationCodeInvalidFormat, pattern.String(), fmt.Sprintf("must be in the format %q", example))
+	}
+}
@@ -0,0 +1,133 @@
+package paynow_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WaitOptions configures WaitForPaymentStatus and WaitForRefundStatus. Zero values fall back to the defaults.
+type WaitOptions struct {
+	Interval    time.Duration // Delay before the second poll, 1s by default
+	MaxInterval time.Duration // Upper bound of the delay between polls, 15s by default
+	Multiplier  float64       // Factor applied to the delay after every poll, 1.5 by default
+	Timeout     time.Duration // Overall time limit, 0 relies only on the context deadline
+}
+
+func (o *WaitOptions) withDefaults() WaitOptions {
+	options := WaitOptions{
+		Interval:    time.Second,
+		MaxInterval: 15 * time.Second,
+		Multiplier:  1.5,
+	}
+	if o == nil {
+		return options
+	}
+	if o.Interval > 0 {
+		options.Interval = o.Interval
+	}
+	if o.MaxInterval > 0 {
+		options.MaxInterval = o.MaxInterval
+	}
+	if o.Multiplier >= 1 {
+		options.Multiplier = o.Multiplier
+	}
+	options.Timeout = o.Timeout
+	return options
+}
+
+// WaitForPaymentStatus polls GetPaymentStatus until the payment reaches a terminal status. It returns the last
+// response and every distinct status observed, in order. When the timeout or context expires first, the last
+// response and history are returned together with the context error. Polls failing with a network error, 429 or
+// a 5xx status are repeated after the usual delay; other errors end the wait.
+func (c *PayNowApiClient) WaitForPaymentStatus(ctx context.Context, paymentId string, opts *WaitOptions) (*GetPaymentStatusResponse, []PaymentStatus, error) {
+	response, history, err := poll(ctx, opts.withDefaults(), func(ctx context.Context) (*GetPaymentStatusResponse, PaymentStatus, error) {
+		response, err := c.GetPaymentStatus(ctx, paymentId)
+		if err != nil {
+			return nil, "", err
+		}
+		return response, response.Status, nil
+	}, PaymentStatus.IsTerminal)
+	if err != nil {
+		return response, history, fmt.Errorf("failed to wait for payment %s: %w", paymentId, err)
+	}
+	return response, history, nil
+}
+
+// WaitForRefundStatus polls GetRefundStatus until the refund reaches a terminal status,
+// see WaitForPaymentStatus for the semantics of the returned values.
+func (c *PayNowApiClient) WaitForRefundStatus(ctx context.Context, refundId string, opts *WaitOptions) (*GetRefundStatusResponse, []RefundStatus, error) {
+	response, history, err := poll(ctx, opts.withDefaults(), func(ctx context.Context) (*GetRefundStatusResponse, RefundStatus, error) {
+		response, err := c.GetRefundStatus(ctx, refundId)
+		if err != nil {
+			return nil, "", err
+		}
+		return response, response.Status, nil
+	}, RefundStatus.IsTerminal)
+	if err != nil {
+		return response, history, fmt.Errorf("failed to wait for refund %s: %w", refundId, err)
+	}
+	return response, history, nil
+}
+
+func poll[R any, S comparable](ctx context.Context, options WaitOptions, fetch func(context.Context) (R, S, error), done func(S) bool) (R, []S, error) {
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	var (
+		last     R
+		history  []S
+		lastErr  error // Transient error of the latest poll, reported when the wait times out
+		interval = options.Interval
+	)
+	for {
+		response, status, err := fetch(ctx)
+		switch {
+		case err == nil:
+			lastErr = nil
+			last = response
+			if len(history) == 0 || history[len(history)-1] != status {
+				history = append(history, status)
+			}
+			if done(status) {
+				return last, history, nil
+			}
+		case isTransientError(ctx, err):
+			lastErr = err
+		default:
+			return last, history, err
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if lastErr != nil {
+				return last, history, fmt.Errorf("%w: last poll failed: %w", ctx.Err(), lastErr)
+			}
+			return last, history, ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(time.Duration(float64(interval)*options.Multiplier), options.MaxInterval)
+	}
+}
+
+// isTransientError reports whether a failed poll is worth repeating: 429 and 5xx responses, and transport errors,
+// including the expiry of WithTimeout, while ctx is still live. Other API errors, invalid requests and undecodable
+// responses end the wait.
+func isTransientError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return !errors.Is(err, ErrInvalidRequest) && !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr)
+}
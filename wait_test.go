@@ -0,0 +1,121 @@
+package paynow_sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Hkozacz/paynow-gosdk"
+	"github.com/Hkozacz/paynow-gosdk/paynowtest"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// settleAfterFailedPoll returns a middleware that, once the first status poll returns, makes the next request
+// fail with statusCode and moves the payment to status, so the wait only ends if it gets past the failed poll.
+func settleAfterFailedPoll(t *testing.T, server *paynowtest.Server, statusCode int, status paynow_sdk.PaymentStatus) (paynow_sdk.Middleware, *atomic.Int32) {
+	t.Helper()
+	var polls atomic.Int32
+	return func(next paynow_sdk.Handler) paynow_sdk.Handler {
+		return func(ctx context.Context, req *paynow_sdk.APIRequest) (*paynow_sdk.APIResponse, error) {
+			resp, err := next(ctx, req)
+			if req.Operation != paynow_sdk.OperationGetPaymentStatus || polls.Add(1) != 1 || err != nil {
+				return resp, err
+			}
+			payment := paynow_sdk.GetPaymentStatusResponse{}
+			if err := json.Unmarshal(resp.Body, &payment); err != nil {
+				t.Errorf("failed to decode the first poll: %v", err)
+				return resp, nil
+			}
+			server.FailNextRequests(1, statusCode)
+			if err := server.SetPaymentStatus(payment.PaymentId, status); err != nil {
+				t.Error(err)
+			}
+			return resp, nil
+		}
+	}, &polls
+}
+
+func newWaitTestServer(t *testing.T) *paynowtest.Server {
+	t.Helper()
+	server := paynowtest.NewServer("key", "secret")
+	t.Cleanup(server.Close)
+	return server
+}
+
+func createTestPayment(t *testing.T, client *paynow_sdk.PayNowApiClient) string {
+	t.Helper()
+	payment, err := client.CreatePayment(context.Background(), &paynow_sdk.CreatePaymentRequest{
+		Amount:      1000,
+		ExternalId:  "order-1",
+		Description: "Order 1",
+		Buyer:       &paynow_sdk.BuyerInfo{Email: "jan.kowalski@example.com"},
+	}, "order-1")
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+	return payment.PaymentId
+}
+
+func TestWaitForPaymentStatusSurvivesTransientErrors(t *testing.T) {
+	for _, statusCode := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			server := newWaitTestServer(t)
+			middleware, polls := settleAfterFailedPoll(t, server, statusCode, paynow_sdk.PaymentStatusConfirmed)
+			client := server.Client(paynow_sdk.WithMiddleware(middleware))
+			defer client.Close()
+			paymentId := createTestPayment(t, client)
+
+			status, history, err := client.WaitForPaymentStatus(context.Background(), paymentId, &paynow_sdk.WaitOptions{
+				Interval: time.Millisecond,
+				Timeout:  5 * time.Second,
+			})
+			if err != nil {
+				t.Fatalf("WaitForPaymentStatus() error = %v", err)
+			}
+			if status.Status != paynow_sdk.PaymentStatusConfirmed {
+				t.Errorf("status = %s, want %s", status.Status, paynow_sdk.PaymentStatusConfirmed)
+			}
+			if want := []paynow_sdk.PaymentStatus{paynow_sdk.PaymentStatusNew, paynow_sdk.PaymentStatusConfirmed}; !slices.Equal(history, want) {
+				t.Errorf("history = %v, want %v", history, want)
+			}
+			if got := polls.Load(); got != 3 {
+				t.Errorf("polled %d times, want 3", got)
+			}
+		})
+	}
+}
+
+func TestWaitForPaymentStatusStopsOnClientErrors(t *testing.T) {
+	server := newWaitTestServer(t)
+	client := server.Client()
+	defer client.Close()
+
+	_, _, err := client.WaitForPaymentStatus(context.Background(), "PNW-MISSING", &paynow_sdk.WaitOptions{
+		Interval: time.Millisecond,
+		Timeout:  5 * time.Second,
+	})
+	var apiErr *paynow_sdk.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("WaitForPaymentStatus() error = %v, want the 404 API error", err)
+	}
+}
+
+func TestWaitForPaymentStatusReportsLastTransientError(t *testing.T) {
+	server := newWaitTestServer(t)
+	client := server.Client()
+	defer client.Close()
+	paymentId := createTestPayment(t, client)
+	server.FailNextRequests(1000, http.StatusServiceUnavailable)
+
+	_, _, err := client.WaitForPaymentStatus(context.Background(), paymentId, &paynow_sdk.WaitOptions{
+		Interval: time.Millisecond,
+		Timeout:  50 * time.Millisecond,
+	})
+	var apiErr *paynow_sdk.APIError
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("WaitForPaymentStatus() error = %v, want the deadline wrapping the 503 API error", err)
+	}
+}
@@ -0,0 +1,153 @@
+package paynow_sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Values of GetPaymentMethodsResponse.Type.
+const (
+	PaymentMethodTypeApplePay  = "APPLE_PAY"
+	PaymentMethodTypeBlik      = "BLIK"
+	PaymentMethodTypeCard      = "CARD"
+	PaymentMethodTypeEcommerce = "ECOMMERCE"
+	PaymentMethodTypeGooglePay = "GOOGLE_PAY"
+	PaymentMethodTypePaypo     = "PAYPO"
+	PaymentMethodTypePbl       = "PBL"
+)
+
+// FindPaymentMethod returns the first ENABLED payment method of the given type, e.g. PaymentMethodTypeGooglePay.
+func FindPaymentMethod(methods []GetPaymentMethodsResponse, methodType string) (PaymentMethod, bool) {
+	for _, group := range methods {
+		if group.Type != methodType {
+			continue
+		}
+		for _, method := range group.PaymentMethods {
+			if method.Enabled() {
+				return method, true
+			}
+		}
+	}
+	return PaymentMethod{}, false
+}
+
+// SetWalletToken sets the Google Pay or Apple Pay payment method and the encrypted token returned by the wallet,
+// e.g. paymentData.paymentMethodData.tokenizationData.token for Google Pay or the JSON of payment.token.paymentData
+// for Apple Pay. Paynow expects the token base64-encoded in authorizationCode, the encoding is done here.
+func (c *CreatePaymentRequest) SetWalletToken(paymentMethodId int64, token string) {
+	c.PaymentMethodId = paymentMethodId
+	c.AuthorizationCode = base64.StdEncoding.EncodeToString([]byte(token))
+}
+
+// isWalletToken reports whether code is a base64-encoded JSON token as set by SetWalletToken.
+func isWalletToken(code string) bool {
+	token, err := base64.StdEncoding.DecodeString(code)
+	return err == nil && json.Valid(token)
+}
+
+// GooglePayConfig holds the merchant settings used by NewGooglePayPaymentDataRequest.
+type GooglePayConfig struct {
+	MerchantId          string   // Google merchant ID from the Google Pay & Wallet Console, required in the PRODUCTION environment
+	MerchantName        string   // Name displayed to the buyer
+	Gateway             string   // Gateway identifier of Paynow in Google Pay, "paynow" when empty
+	GatewayMerchantId   string   // Merchant ID assigned by Paynow
+	CountryCode         string   // ISO 3166-1 alpha-2 country code of the merchant, "PL" when empty
+	AllowedCardNetworks []string // "MASTERCARD" and "VISA" when empty
+	AllowedAuthMethods  []string // "PAN_ONLY" and "CRYPTOGRAM_3DS" when empty
+}
+
+// GooglePayPaymentDataRequest is the PaymentDataRequest object passed to the Google Pay JavaScript API.
+type GooglePayPaymentDataRequest struct {
+	ApiVersion            int                      `json:"apiVersion"`
+	ApiVersionMinor       int                      `json:"apiVersionMinor"`
+	AllowedPaymentMethods []GooglePayPaymentMethod `json:"allowedPaymentMethods"`
+	MerchantInfo          GooglePayMerchantInfo    `json:"merchantInfo"`
+	TransactionInfo       GooglePayTransactionInfo `json:"transactionInfo"`
+}
+
+type GooglePayPaymentMethod struct {
+	Type                      string                             `json:"type"` // Always "CARD"
+	Parameters                GooglePayCardParameters            `json:"parameters"`
+	TokenizationSpecification GooglePayTokenizationSpecification `json:"tokenizationSpecification"`
+}
+
+type GooglePayCardParameters struct {
+	AllowedAuthMethods  []string `json:"allowedAuthMethods"`
+	AllowedCardNetworks []string `json:"allowedCardNetworks"`
+}
+
+type GooglePayTokenizationSpecification struct {
+	Type       string            `json:"type"` // Always "PAYMENT_GATEWAY"
+	Parameters map[string]string `json:"parameters"`
+}
+
+type GooglePayMerchantInfo struct {
+	MerchantId   string `json:"merchantId,omitempty"`
+	MerchantName string `json:"merchantName,omitempty"`
+}
+
+type GooglePayTransactionInfo struct {
+	TotalPriceStatus string `json:"totalPriceStatus"` // Always "FINAL"
+	TotalPrice       string `json:"totalPrice"`       // Amount in major units, e.g. "10.00"
+	CurrencyCode     string `json:"currencyCode"`
+	CountryCode      string `json:"countryCode"`
+}
+
+// NewGooglePayPaymentDataRequest builds the Google Pay PaymentDataRequest for a payment of amount (in the smallest
+// currency unit) in currency, from the response of GetPaymentMethods called with the same amount and currency.
+// It returns an error wrapping ErrPaymentMethodUnavailable when Google Pay is not enabled.
+// Pass the token from the Google Pay response to CreatePaymentRequest.SetWalletToken with the ID of the method
+// returned by FindPaymentMethod(methods, PaymentMethodTypeGooglePay).
+func NewGooglePayPaymentDataRequest(methods []GetPaymentMethodsResponse, amount int64, currency string, config GooglePayConfig) (*GooglePayPaymentDataRequest, error) {
+	if _, ok := FindPaymentMethod(methods, PaymentMethodTypeGooglePay); !ok {
+		return nil, fmt.Errorf("%w: %s is not enabled", ErrPaymentMethodUnavailable, PaymentMethodTypeGooglePay)
+	}
+	var errs ValidationErrors
+	errs.inRange("amount", amount, 1, 9999999999)
+	errs.oneOf("currency", currency, supportedCurrencies)
+	errs.required("gatewayMerchantId", config.GatewayMerchantId)
+	if err := errs.errOrNil(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	if config.Gateway == "" {
+		config.Gateway = "paynow"
+	}
+	if config.CountryCode == "" {
+		config.CountryCode = "PL"
+	}
+	if len(config.AllowedCardNetworks) == 0 {
+		config.AllowedCardNetworks = []string{"MASTERCARD", "VISA"}
+	}
+	if len(config.AllowedAuthMethods) == 0 {
+		config.AllowedAuthMethods = []string{"PAN_ONLY", "CRYPTOGRAM_3DS"}
+	}
+	return &GooglePayPaymentDataRequest{
+		ApiVersion:      2,
+		ApiVersionMinor: 0,
+		AllowedPaymentMethods: []GooglePayPaymentMethod{{
+			Type: "CARD",
+			Parameters: GooglePayCardParameters{
+				AllowedAuthMethods:  config.AllowedAuthMethods,
+				AllowedCardNetworks: config.AllowedCardNetworks,
+			},
+			TokenizationSpecification: GooglePayTokenizationSpecification{
+				Type: "PAYMENT_GATEWAY",
+				Parameters: map[string]string{
+					"gateway":           config.Gateway,
+					"gatewayMerchantId": config.GatewayMerchantId,
+				},
+			},
+		}},
+		MerchantInfo: GooglePayMerchantInfo{
+			MerchantId:   config.MerchantId,
+			MerchantName: config.MerchantName,
+		},
+		TransactionInfo: GooglePayTransactionInfo{
+			TotalPriceStatus: "FINAL",
+			TotalPrice:       fmt.Sprintf("%d.%02d", amount/100, amount%100),
+			CurrencyCode:     currency,
+			CountryCode:      config.CountryCode,
+		},
+	}, nil
+}